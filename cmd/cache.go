@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sebakri/box/internal/store"
+)
+
+var cacheGCDryRun bool
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manages the shared tool store",
+	Long: `Manages the shared tool store used when shared_store is enabled in box.yml or BOX_SHARED_STORE=1 is set.
+Tools are installed once per definition and platform and linked into the .box/bin of each project.`,
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the entries of the shared store and the projects using them",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		s, entries, err := storeEntries()
+		if err != nil {
+			return err
+		}
+		refs, err := s.References()
+		if err != nil {
+			return err
+		}
+
+		users := make(map[string][]string)
+		for project, keys := range refs {
+			for _, key := range keys {
+				users[key] = append(users[key], project)
+			}
+		}
+
+		fmt.Println(titleStyle.Render(fmt.Sprintf("Shared store (%s):", s.Dir)))
+		if len(entries) == 0 {
+			fmt.Println("The shared store is empty.")
+		}
+		for _, e := range entries {
+			version := e.Version
+			if version == "" {
+				version = "latest"
+			}
+			fmt.Printf("• %s %s %s\n", toolStyle.Render(e.Source), typeStyle.Render("("+e.Type+", "+version+", "+e.Platform+")"), typeStyle.Render(e.Key[:12]))
+			projects := users[e.Key]
+			sort.Strings(projects)
+			if len(projects) == 0 {
+				fmt.Printf("  %s\n", warnStyle.Render("unused"))
+			} else {
+				fmt.Printf("  %s %s\n", typeStyle.Render("used by:"), strings.Join(projects, ", "))
+			}
+		}
+		return nil
+	},
+}
+
+var cacheSizeCmd = &cobra.Command{
+	Use:   "size",
+	Short: "Shows the disk usage of the shared store",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		s, entries, err := storeEntries()
+		if err != nil {
+			return err
+		}
+
+		var total int64
+		for _, e := range entries {
+			size, err := s.Size(e.Key)
+			if err != nil {
+				return fmt.Errorf("failed to compute size of %s: %w", e.Key, err)
+			}
+			total += size
+			fmt.Printf("%10s  %s %s\n", formatSize(size), e.Source, typeStyle.Render(e.Version))
+		}
+		fmt.Printf("%10s  %s\n", formatSize(total), titleStyle.Render(fmt.Sprintf("total (%d entries)", len(entries))))
+		return nil
+	},
+}
+
+var cacheGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Removes entries no longer used by any registered project",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		s, err := store.Default()
+		if err != nil {
+			return err
+		}
+
+		removed, err := s.GC(cacheGCDryRun)
+		for _, e := range removed {
+			prefix := successStyle.Render("-")
+			if cacheGCDryRun {
+				prefix = warnStyle.Render("would remove")
+			}
+			fmt.Printf("%s %s %s\n", prefix, e.Source, typeStyle.Render("("+e.Type+", "+e.Key[:12]+")"))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to clean up shared store: %w", err)
+		}
+		if len(removed) == 0 {
+			fmt.Println("No unused entries.")
+		}
+		return nil
+	},
+}
+
+func init() {
+	cacheGCCmd.Flags().BoolVar(&cacheGCDryRun, "dry-run", false, "Show what would be removed without deleting anything")
+	cacheCmd.AddCommand(cacheListCmd, cacheSizeCmd, cacheGCCmd)
+	RootCmd.AddCommand(cacheCmd)
+}
+
+func storeEntries() (*store.Store, []store.Entry, error) {
+	s, err := store.Default()
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, err := s.Entries()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read shared store: %w", err)
+	}
+	return s, entries, nil
+}
+
+// formatSize formats a size in bytes for display.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// cacheKeyCmd represents the cache-key command
+var cacheKeyCmd = &cobra.Command{
+	Use:   "cache-key",
+	Short: "Print a cache key for the .box directory",
+	Long: `Prints a key derived from the platform and the files that determine the tools: box.yml,
+the files it extends, go.mod and go.sum with go_mod_tools, and the configurations of
+workspace members. Use it to cache the .box directory in custom CI pipelines.`,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		path, _, err := resolveConfig()
+		if err != nil {
+			return err
+		}
+
+		key, err := config.CacheKey(path)
+		if err != nil {
+			return fmt.Errorf("failed to compute cache key: %w", err)
+		}
+
+		fmt.Println(key)
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(cacheKeyCmd)
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/importer"
+)
+
+var (
+	projectDir     string
+	configFile     string
+	configResolved bool
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Shows the files that make up the configuration",
+	Long: `Shows the files merged into the configuration via extends, in merge order.
+With --resolved, prints the final merged configuration with each tool and env variable annotated by the file it was defined in.`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		path, root, err := resolveConfig()
+		if err != nil {
+			return err
+		}
+		cfg, err := loadConfig(path)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+
+		if !configResolved {
+			for _, file := range cfg.Files {
+				fmt.Println(file)
+			}
+			return nil
+		}
+
+		data, err := cfg.MarshalResolved(root)
+		if err != nil {
+			return fmt.Errorf("failed to render configuration: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	},
+}
+
+func init() {
+	configCmd.Flags().BoolVar(&configResolved, "resolved", false, "Print the merged configuration annotated with the origin of each entry")
+	RootCmd.AddCommand(configCmd)
+}
+
+// resolveConfig returns the configuration file to use and the project root
+// containing it. The file given with --file takes precedence over discovery.
+func resolveConfig() (string, string, error) {
+	if configFile != "" {
+		path, err := filepath.Abs(configFile)
+		if err != nil {
+			return "", "", err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return "", "", fmt.Errorf("configuration file %s not found", configFile)
+		}
+		return path, filepath.Dir(path), nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	path, err := config.Discover(cwd)
+	if err != nil {
+		return "", "", err
+	}
+	return path, filepath.Dir(path), nil
+}
+
+// resolveProject is like resolveConfig but falls back to box.yml in the
+// working directory for commands that work without a configuration.
+func resolveProject() (string, string, error) {
+	path, root, err := resolveConfig()
+	if err == nil || configFile != "" {
+		return path, root, err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	return filepath.Join(cwd, config.FileNames[0]), cwd, nil
+}
+
+// loadConfig loads the configuration file and applies settings that pull tools
+// from other sources, such as the tool directives of go.mod.
+func loadConfig(configFile string) (*config.Config, error) {
+	return loadConfigWith(config.Load, configFile)
+}
+
+// loadConfigWith is like loadConfig but loads the configuration file with load,
+// e.g. config.LoadCommitted to leave out box.local.yml and other overlays.
+func loadConfigWith(load func(string) (*config.Config, error), configFile string) (*config.Config, error) {
+	cfg, err := load(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.GoModTools {
+		if err := importer.SyncGoModTools(cfg, filepath.Dir(configFile)); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// overlayLabel returns a marker for entries defined in box.local.yml or another overlay file.
+func overlayLabel(cfg *config.Config, origin string) string {
+	if !cfg.FromOverlay(origin) {
+		return ""
+	}
+	if filepath.Base(origin) == config.LocalFile {
+		return " " + warnStyle.Render("(local)")
+	}
+	return " " + warnStyle.Render("(overlay: "+filepath.Base(origin)+")")
+}
+
+// loadProject loads the configuration at path for commands that work with the
+// installed tools. At a workspace root it returns the union of the tools of all
+// members. Inside a workspace member it returns the member's view of the
+// workspace. The returned directory holds the .box directory to use.
+func loadProject(path, root string) (*config.Config, string, error) {
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if cfg.Workspace != nil {
+		members, err := loadMembers(cfg, root)
+		if err != nil {
+			return nil, "", err
+		}
+		union, err := cfg.Union(members)
+		if err != nil {
+			return nil, "", err
+		}
+		return union, root, nil
+	}
+
+	workspacePath, err := config.FindWorkspace(root)
+	if err != nil || workspacePath == "" {
+		return cfg, root, err
+	}
+	workspace, err := loadConfig(workspacePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load workspace %s: %w", workspacePath, err)
+	}
+	view, err := workspace.MemberView(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	return view, filepath.Dir(workspacePath), nil
+}
+
+// loadOptionalProject is like loadProject for commands that also work
+// without a configuration. Only if the file at path does not exist, it
+// returns an empty configuration rooted at root; other errors are returned.
+func loadOptionalProject(path, root string) (*config.Config, string, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return &config.Config{}, root, nil
+	}
+	cfg, root, err := loadProject(path, root)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	return cfg, root, nil
+}
+
+// loadMembers loads the members of the workspace rooted at root.
+func loadMembers(cfg *config.Config, root string) ([]config.Member, error) {
+	paths, err := cfg.Workspace.MemberPaths(root)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]config.Member, 0, len(paths))
+	for _, path := range paths {
+		memberCfg, err := loadConfig(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load workspace member %s: %w", path, err)
+		}
+		name, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, config.Member{Name: filepath.ToSlash(name), Path: path, Config: memberCfg})
+	}
+	return members, nil
+}
+
+// relativePath returns path relative to the working directory if possible, for display.
+func relativePath(path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(cwd, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// registries returns the package registry mirrors of cfg on top of the ones in
+// the user configuration.
+func registries(cfg *config.Config) (map[string]config.Registry, error) {
+	user, err := config.LoadUserConfig()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.ResolveRegistries(user), nil
+}
@@ -9,10 +9,19 @@ import (
 
 // doctorCmd represents the doctor command
 var doctorCmd = &cobra.Command{
-	Use:   "doctor",
-	Short: "Checks if the host runtimes are installed",
-	Run: func(_ *cobra.Command, _ []string) {
+	Use:         "doctor",
+	Short:       "Checks if the host runtimes are installed",
+	Annotations: supportsJSON,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		if jsonOutput() {
+			results := doctor.Check()
+			return printJSON(struct {
+				Runtimes []doctor.Result `json:"runtimes"`
+				Ready    bool            `json:"ready"`
+			}{results, doctor.Ready(results)})
+		}
 		doctor.Run()
+		return nil
 	},
 }
 
@@ -6,8 +6,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/sebakri/box/internal/config"
-
 	"github.com/spf13/cobra"
 )
 
@@ -16,17 +14,17 @@ var envCmd = &cobra.Command{
 	Use:   "env [key]",
 	Short: "Display the merged list of environment variables",
 	RunE: func(_ *cobra.Command, args []string) error {
-		configFile, err := findNearestBoxConfig()
+		path, root, err := resolveConfig()
 		if err != nil {
-			return fmt.Errorf("could not find box.yml: %w", err)
+			return err
 		}
 
-		cfg, err := config.Load(configFile)
+		cfg, root, err := loadProject(path, root)
 		if err != nil {
-			cfg = &config.Config{}
+			return fmt.Errorf("failed to load %s: %w", path, err)
 		}
 
-		boxDir := filepath.Dir(configFile)
+		boxDir := filepath.Join(root, ".box")
 		binDir := filepath.Join(boxDir, "bin")
 
 		// Get current environment and merge with box.yml env and updated PATH
@@ -72,6 +70,10 @@ var envCmd = &cobra.Command{
 
 		// Print in KEY=VALUE format
 		for k, v := range envMap {
+			// Mark values that come from box.local.yml or another overlay
+			if origin, ok := cfg.EnvOrigin[k]; ok && cfg.FromOverlay(origin) {
+				fmt.Printf("# from %s\n", filepath.Base(origin))
+			}
 			fmt.Printf("%s=%s\n", k, v)
 		}
 		return nil
@@ -81,25 +83,3 @@ var envCmd = &cobra.Command{
 func init() {
 	RootCmd.AddCommand(envCmd)
 }
-
-func findNearestBoxConfig() (string, error) {
-	dir, err := os.Getwd()
-	if err != nil {
-		return "", err
-	}
-
-	for {
-		configPath := filepath.Join(dir, "box.yml")
-		if _, err := os.Stat(configPath); err == nil {
-			return configPath, nil
-		}
-
-		parentDir := filepath.Dir(dir)
-		if parentDir == dir {
-			break // Reached root directory
-		}
-		dir = parentDir
-	}
-
-	return "", fmt.Errorf("box.yml not found in current or parent directories")
-}
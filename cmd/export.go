@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/exporter"
+	"github.com/sebakri/box/internal/importer"
+)
+
+var (
+	exportTo       string
+	exportWrite    bool
+	exportMappings []string
+	exportLocal    bool
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports box.yml to the formats of other tool managers",
+	Long: `Exports the tools and environment variables of box.yml to mise.toml, .tool-versions or a Nix flake devShell.
+Entries that cannot be represented in the target format are written as comments. The output is deterministic so it can be committed and checked in CI.
+Tools and env from box.local.yml and BOX_CONFIG_OVERLAY are left out unless --include-local is given.`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		format, ok := exporter.Formats[exportTo]
+		if !ok {
+			return fmt.Errorf("unsupported export format: %s (supported: %s)", exportTo, strings.Join(exporter.FormatNames(), ", "))
+		}
+
+		path, root, err := resolveConfig()
+		if err != nil {
+			return err
+		}
+
+		// Overlays are private to a machine and must not end up in committed files
+		load := config.LoadCommitted
+		if exportLocal {
+			load = config.Load
+		}
+		cfg, err := loadConfigWith(load, path)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+
+		mappings, err := importer.LoadMappings(exportMappings...)
+		if err != nil {
+			return fmt.Errorf("failed to load mappings: %w", err)
+		}
+
+		data, err := exporter.Export(exportTo, cfg, mappings)
+		if err != nil {
+			return err
+		}
+
+		if !exportWrite {
+			fmt.Print(string(data))
+			return nil
+		}
+
+		if err := os.WriteFile(filepath.Join(root, format.File), data, 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", format.File, err)
+		}
+		fmt.Printf("%s Generated %s\n", successStyle.Render("✅"), format.File)
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportTo, "to", "", "Format to export to ("+strings.Join(exporter.FormatNames(), ", ")+")")
+	exportCmd.Flags().BoolVar(&exportWrite, "write", false, "Write to the default file of the format instead of stdout")
+	exportCmd.Flags().StringArrayVar(&exportMappings, "mappings", nil, "Additional mapping file(s) extending the built-in tool mappings")
+	exportCmd.Flags().BoolVar(&exportLocal, "include-local", false, "Include tools and env from box.local.yml and BOX_CONFIG_OVERLAY")
+	_ = exportCmd.MarkFlagRequired("to")
+	RootCmd.AddCommand(exportCmd)
+}
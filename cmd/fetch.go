@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sebakri/box/internal/installer"
+
+	"github.com/spf13/cobra"
+)
+
+// fetchCmd represents the fetch command
+var fetchCmd = &cobra.Command{
+	Use:   "fetch",
+	Short: "Downloads the artifacts of all tools into .box/cache for offline installs",
+	Long: `Downloads everything needed to install the tools defined in box.yml into .box/cache:
+Go modules, npm packages, Python wheels, gem files and prebuilt crates.
+Afterwards 'box install --offline' installs the tools without network access.
+Script tools cannot be fetched and are skipped.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		path, root, err := resolveConfig()
+		if err != nil {
+			return err
+		}
+
+		cfg, root, err := loadProject(path, root)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+
+		tempDir, err := os.MkdirTemp("", "box-fetch-*")
+		if err != nil {
+			return fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		defer func() {
+			_ = os.RemoveAll(tempDir)
+		}()
+
+		mgr, err := newManager(cfg, root, path, tempDir)
+		if err != nil {
+			return err
+		}
+
+		failed := 0
+		for _, tool := range cfg.Tools {
+			fmt.Printf("• Fetching %s...\n", tool.DisplayName())
+			if err := mgr.Fetch(tool); err != nil {
+				if errors.Is(err, installer.ErrNotFetchable) {
+					fmt.Printf("%s Skipped %s: %s tools cannot be fetched\n", warnStyle.Render("⚠️"), tool.DisplayName(), tool.Type)
+					continue
+				}
+				fmt.Printf("%s Failed to fetch %s: %v\n", warnStyle.Render("✗"), tool.DisplayName(), err)
+				failed++
+				continue
+			}
+			fmt.Printf("✅ Fetched %s\n", tool.DisplayName())
+		}
+
+		if failed > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to fetch %d tools", failed)
+		}
+		fmt.Printf("All artifacts are in %s. Run 'box install --offline' to install without network access.\n", relativePath(filepath.Join(root, ".box", "cache")))
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(fetchCmd)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 
@@ -11,25 +12,32 @@ import (
 	"github.com/sebakri/box/internal/installer"
 )
 
+var (
+	dockerfileOpts installer.DockerfileOptions
+	ciProvider     string
+)
+
 // generateCmd represents the generate command
 var generateCmd = &cobra.Command{
 	Use:       "generate <type>",
 	Short:     "Generates configuration files",
-	Long:      `Generates configuration files for shell integration or containerization (e.g., direnv, dockerfile).`,
-	ValidArgs: []string{"direnv", "dockerfile"},
+	Long:      `Generates configuration files for shell integration or containerization (e.g., direnv, dockerfile, devcontainer, ci).`,
+	ValidArgs: []string{"direnv", "dockerfile", "devcontainer", "ci"},
 	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
 	RunE: func(_ *cobra.Command, args []string) error {
 		genType := args[0]
 
-		configFile := "box.yml"
-		cfg, err := config.Load(configFile)
+		path, root, err := resolveProject()
 		if err != nil {
-			cfg = &config.Config{}
+			return err
 		}
 
-		cwd, err := os.Getwd()
-		if err != nil {
-			return fmt.Errorf("failed to get current working directory: %w", err)
+		// Without box.yml the defaults are generated
+		cfg := &config.Config{}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			if cfg, err = loadConfig(path); err != nil {
+				return fmt.Errorf("failed to load %s: %w", path, err)
+			}
 		}
 
 		// Create a specific temp directory for this session
@@ -41,9 +49,13 @@ var generateCmd = &cobra.Command{
 			_ = os.RemoveAll(tempDir)
 		}()
 
-		mgr := installer.New(cwd, tempDir, cfg.Env, cfg)
+		mgr := installer.New(root, tempDir, cfg.Env, cfg)
+		mgr.ConfigFile = path
 		mgr.Output = io.Discard
 
+		// The image installs the same release of box
+		dockerfileOpts.BoxVersion = Version
+
 		switch genType {
 		case "direnv":
 			if err := mgr.EnsureEnvrc(); err != nil {
@@ -54,15 +66,38 @@ var generateCmd = &cobra.Command{
 				fmt.Printf("%s Failed to run direnv allow: %v\n", warnStyle.Render("⚠️"), err)
 			}
 		case "dockerfile":
-			if err := mgr.GenerateDockerfile(); err != nil {
+			if err := mgr.GenerateDockerfile(dockerfileOpts); err != nil {
 				return fmt.Errorf("failed to generate Dockerfile: %w", err)
 			}
 			fmt.Printf("%s Generated Dockerfile\n", successStyle.Render("✅"))
+		case "devcontainer":
+			// The devcontainer builds from the project Dockerfile, so create one if missing
+			if _, err := os.Stat(filepath.Join(root, "Dockerfile")); os.IsNotExist(err) {
+				if err := mgr.GenerateDockerfile(dockerfileOpts); err != nil {
+					return fmt.Errorf("failed to generate Dockerfile: %w", err)
+				}
+				fmt.Printf("%s Generated Dockerfile\n", successStyle.Render("✅"))
+			}
+			if err := mgr.GenerateDevcontainer(); err != nil {
+				return fmt.Errorf("failed to generate devcontainer.json: %w", err)
+			}
+			fmt.Printf("%s Generated .devcontainer/devcontainer.json\n", successStyle.Render("✅"))
+		case "ci":
+			path, err := mgr.GenerateCI(ciProvider)
+			if err != nil {
+				return fmt.Errorf("failed to generate CI configuration: %w", err)
+			}
+			fmt.Printf("%s Generated %s\n", successStyle.Render("✅"), path)
 		}
 		return nil
 	},
 }
 
 func init() {
+	generateCmd.Flags().StringVar(&dockerfileOpts.BaseImage, "base-image", installer.DefaultBaseImage, "Base image for the generated Dockerfile")
+	generateCmd.Flags().StringVar(&dockerfileOpts.Platform, "platform", "", "Target platform for the generated Dockerfile (e.g. linux/arm64)")
+	generateCmd.Flags().BoolVar(&dockerfileOpts.MultiStage, "multi-stage", false, "Install tools in a builder stage and copy only .box to the final image")
+	generateCmd.Flags().StringVar(&dockerfileOpts.Template, "template", "", "Custom Dockerfile template file")
+	generateCmd.Flags().StringVar(&ciProvider, "provider", "github", "CI provider to generate a pipeline for (github, gitlab)")
 	RootCmd.AddCommand(generateCmd)
 }
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/importer"
+	"github.com/sebakri/box/internal/installer"
+)
+
+var (
+	importFrom     string
+	importMappings []string
+	importDryRun   bool
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Imports tool definitions from other tool managers into box.yml",
+	Long: `Imports tool definitions from other tool managers (e.g. asdf, mise, aqua, go.mod, package.json, pyproject.toml) into box.yml.
+Existing comments and entries in box.yml are preserved and tools that are already defined are skipped.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		// box import can create the configuration, so --file doesn't need to exist yet
+		configPath, root := configFile, filepath.Dir(configFile)
+		if configFile == "" {
+			var err error
+			if configPath, root, err = resolveProject(); err != nil {
+				return err
+			}
+		}
+
+		var path string
+		var err error
+		if len(args) > 0 {
+			path = args[0]
+		} else {
+			path, err = importer.DefaultPath(importFrom, root)
+			if err != nil {
+				return err
+			}
+		}
+
+		mappings, err := importer.LoadMappings(importMappings...)
+		if err != nil {
+			return fmt.Errorf("failed to load mappings: %w", err)
+		}
+
+		result, err := importer.Import(importFrom, path, mappings)
+		if err != nil {
+			return err
+		}
+
+		// Only keep tools that box can actually install
+		tools := make([]config.Tool, 0, len(result.Tools))
+		for _, t := range result.Tools {
+			if _, ok := installer.SupportedTools[t.Type]; !ok {
+				result.Unmapped = append(result.Unmapped, importer.Unmapped{Name: t.DisplayName(), Reason: fmt.Sprintf("unsupported tool type %q in mapping", t.Type)})
+				continue
+			}
+			tools = append(tools, t)
+		}
+
+		added := tools
+		if !importDryRun {
+			added, err = config.AppendTools(configPath, tools)
+			if err != nil {
+				return fmt.Errorf("failed to update %s: %w", relativePath(configPath), err)
+			}
+		}
+
+		fmt.Println(titleStyle.Render(fmt.Sprintf("Imported from %s:", path)))
+		if len(added) == 0 {
+			fmt.Println("No new tools to add.")
+		}
+		for _, t := range added {
+			version := t.Version
+			if version == "" {
+				version = "latest"
+			}
+			fmt.Printf("%s %s %s\n", successStyle.Render("+"), toolStyle.Render(t.DisplayName()), typeStyle.Render("("+t.Type+", "+version+")"))
+		}
+
+		if len(result.Unmapped) > 0 {
+			fmt.Println()
+			fmt.Println(warnStyle.Render("Could not map the following entries:"))
+			for _, u := range result.Unmapped {
+				fmt.Printf("%s %s %s\n", warnStyle.Render("•"), u.Name, typeStyle.Render("("+u.Reason+")"))
+			}
+		}
+
+		if importDryRun {
+			fmt.Printf("\nDry run: %s was not modified.\n", relativePath(configPath))
+		}
+		return nil
+	},
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFrom, "from", "", "Format to import from ("+strings.Join(importer.FormatNames(), ", ")+")")
+	importCmd.Flags().StringArrayVar(&importMappings, "mappings", nil, "Additional mapping file(s) extending the built-in tool mappings")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show what would be imported without modifying box.yml")
+	_ = importCmd.MarkFlagRequired("from")
+	RootCmd.AddCommand(importCmd)
+}
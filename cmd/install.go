@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/sebakri/box/internal/config"
 	"github.com/sebakri/box/internal/installer"
+	"github.com/sebakri/box/internal/logging"
+	"github.com/sebakri/box/internal/store"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -29,11 +34,13 @@ const (
 	statusInstalling
 	statusDone
 	statusFailed
+	statusSkipped
 )
 
 type installMsg struct {
-	index int
-	err   error
+	index   int
+	err     error
+	logTail []string
 }
 
 type outputMsg struct {
@@ -46,33 +53,56 @@ type toolTask struct {
 	status     toolStatus
 	err        error
 	lastOutput string
+	logTail    []string // Last lines of the install log of a failed tool
 }
 
 type model struct {
 	tasks    []toolTask
-	index    int
-	indexPtr *int
+	indexPtr *int // Task of the running tool, shared with the progressWriter
 	spinner  spinner.Model
 	quitting bool
 	manager  *installer.Manager
-	tools    []config.Tool
+	plan     *installer.Plan
+	tools    []config.Tool // Tools of the tasks, in install order
 }
 
 func (m model) Init() tea.Cmd {
 	return tea.Batch(m.spinner.Tick, m.installNext())
 }
 
+// installNext starts the next tool of the plan that is ready to install.
 func (m model) installNext() tea.Cmd {
-	if m.index >= len(m.tasks) {
+	tool, ok := m.plan.Next()
+	if !ok {
 		return tea.Quit
 	}
 
+	index := m.taskIndex(tool.DisplayName())
+	if m.indexPtr != nil {
+		*m.indexPtr = index
+	}
+	m.tasks[index].status = statusInstalling
+
+	mgr := m.manager
 	return func() tea.Msg {
-		err := m.manager.Install(m.tools[m.index])
-		return installMsg{index: m.index, err: err}
+		err := mgr.Install(tool)
+		msg := installMsg{index: index, err: err}
+		if err != nil {
+			msg.logTail = mgr.LogTail(tool.DisplayName(), logTailLines)
+		}
+		return msg
 	}
 }
 
+func (m model) taskIndex(name string) int {
+	for i, t := range m.tools {
+		if t.DisplayName() == name {
+			return i
+		}
+	}
+	return -1
+}
+
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -87,21 +117,20 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case installMsg:
+		name := m.tasks[msg.index].name
 		if msg.err != nil {
 			m.tasks[msg.index].status = statusFailed
 			m.tasks[msg.index].err = msg.err
+			m.tasks[msg.index].logTail = msg.logTail
 		} else {
 			m.tasks[msg.index].status = statusDone
 		}
-		m.index++
-		if m.indexPtr != nil {
-			*m.indexPtr = m.index
-		}
-		if m.index < len(m.tasks) {
-			m.tasks[m.index].status = statusInstalling
-			return m, m.installNext()
+		for _, skipped := range m.plan.Finish(name, msg.err) {
+			i := m.taskIndex(skipped.DisplayName())
+			m.tasks[i].status = statusSkipped
+			m.tasks[i].err = installer.DependencyError{Dependency: name}
 		}
-		return m, tea.Quit
+		return m, m.installNext()
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
@@ -121,8 +150,8 @@ func (m model) View() string {
 	statusStyle := lipgloss.NewStyle().Width(2).Align(lipgloss.Center)
 	outputStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).MarginLeft(6)
 
-	failedCount := 0
-	for i, t := range m.tasks {
+	failedCount, skippedCount := 0, 0
+	for _, t := range m.tasks {
 		status := " "
 		switch t.status {
 		case statusInstalling:
@@ -132,25 +161,39 @@ func (m model) View() string {
 		case statusFailed:
 			status = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("✗")
 			failedCount++
+		case statusSkipped:
+			status = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("-")
+			skippedCount++
 		}
 
 		s += fmt.Sprintf("  %s %s\n", statusStyle.Render(status), t.name)
 		if t.status == statusInstalling && t.lastOutput != "" {
 			s += outputStyle.Render(t.lastOutput) + "\n"
 		}
-		if t.err != nil {
+		if t.status == statusSkipped {
+			s += outputStyle.Render(fmt.Sprintf("Skipped: %v", t.err)) + "\n"
+		} else if t.err != nil {
 			s += lipgloss.NewStyle().Foreground(lipgloss.Color("1")).MarginLeft(6).Render(fmt.Sprintf("Error: %v", t.err)) + "\n"
+			for _, line := range t.logTail {
+				s += outputStyle.Render(line) + "\n"
+			}
+			if len(t.logTail) > 0 {
+				s += outputStyle.Render(fmt.Sprintf("Run 'box logs %s' for the full log.", t.name)) + "\n"
+			}
 		}
-		_ = i
 	}
 
 	if m.quitting {
 		return s + "\n"
 	}
 
-	if m.index >= len(m.tasks) {
+	if m.plan.Done() {
 		if failedCount > 0 {
-			return s + lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Margin(1, 2).Render(fmt.Sprintf("Installation finished with %d errors.", failedCount)) + "\n"
+			summary := fmt.Sprintf("Installation finished with %d errors.", failedCount)
+			if skippedCount > 0 {
+				summary = fmt.Sprintf("Installation finished with %d errors, %d tools skipped.", failedCount, skippedCount)
+			}
+			return s + lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Margin(1, 2).Render(summary) + "\n"
 		}
 		return s + doneStyle.Render("All tools installed successfully! ✨") + "\n"
 	}
@@ -179,28 +222,28 @@ func (w *progressWriter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
+// logTailLines is the number of install log lines shown for a failed tool.
+const logTailLines = 8
+
 var (
 	nonInteractive bool
-	configFile     string
+	offline        bool
 )
 
 // installCmd represents the install command
 var installCmd = &cobra.Command{
-	Use:   "install",
-	Short: "Installs tools defined in box.yml",
-	RunE: func(_ *cobra.Command, _ []string) error {
-		if _, err := os.Stat(configFile); os.IsNotExist(err) {
-			return fmt.Errorf("configuration file %s not found", configFile)
-		}
-
-		cfg, err := config.Load(configFile)
+	Use:         "install",
+	Short:       "Installs tools defined in box.yml",
+	Annotations: supportsJSON,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		path, root, err := resolveConfig()
 		if err != nil {
-			return fmt.Errorf("failed to load %s: %w", configFile, err)
+			return err
 		}
 
-		cwd, err := os.Getwd()
+		cfg, root, err := loadProject(path, root)
 		if err != nil {
-			return fmt.Errorf("failed to get current working directory: %w", err)
+			return fmt.Errorf("failed to load %s: %w", path, err)
 		}
 
 		// Create a specific temp directory for this session
@@ -212,30 +255,70 @@ var installCmd = &cobra.Command{
 			_ = os.RemoveAll(tempDir)
 		}()
 
-		mgr := installer.New(cwd, tempDir, cfg.Env, cfg)
+		mgr, err := newManager(cfg, root, path, tempDir)
+		if err != nil {
+			return err
+		}
+		mgr.Offline = offline
+
+		plan, err := installer.NewPlan(cfg.Tools)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			cmd.SilenceUsage = true
+			return installJSON(mgr, plan)
+		}
 
-		if nonInteractive {
-			fmt.Println("Starting tool installation (non-interactive)...")
-			for _, tool := range cfg.Tools {
-				fmt.Printf("• Installing %s...\n", tool.DisplayName())
-				if err := mgr.Install(tool); err != nil {
-					return fmt.Errorf("failed to install %s: %w", tool.DisplayName(), err)
+		// Without a terminal the interactive progress view cannot be shown
+		if nonInteractive || quiet || !isTerminal(os.Stdout) {
+			progress := func(format string, a ...any) {
+				if !quiet {
+					fmt.Printf(format, a...)
 				}
-				fmt.Printf("✅ Successfully installed %s\n", tool.DisplayName())
 			}
-			fmt.Println("All tools installed successfully! ✨")
+			if quiet {
+				// The installer output is still kept in .box/logs, warnings go to stderr
+				mgr.Output = io.Discard
+				mgr.LogHandler = logging.Multi(mgr.LogHandler, logging.NewHandler(os.Stderr, slog.LevelWarn))
+			}
+
+			progress("Starting tool installation (non-interactive)...\n")
+			var failed []string
+			for tool, ok := plan.Next(); ok; tool, ok = plan.Next() {
+				name := tool.DisplayName()
+				progress("• Installing %s...\n", name)
+				err := mgr.Install(tool)
+				if err != nil {
+					failed = append(failed, name)
+					fmt.Printf("%s Failed to install %s: %v\n", warnStyle.Render("✗"), name, err)
+					if log, _ := mgr.LatestLog(name); log != "" {
+						fmt.Printf("Install log: %s\n", relativePath(log))
+					}
+				} else {
+					progress("✅ Successfully installed %s\n", name)
+				}
+				for _, skipped := range plan.Finish(name, err) {
+					failed = append(failed, skipped.DisplayName())
+					fmt.Printf("%s Skipped %s: %v\n", warnStyle.Render("⚠️"), skipped.DisplayName(), installer.DependencyError{Dependency: name})
+				}
+			}
+			if len(failed) > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to install %s", strings.Join(failed, ", "))
+			}
+			progress("All tools installed successfully! ✨\n")
 			return nil
 		}
 
 		mgr.Output = io.Discard
 
-		tasks := make([]toolTask, len(cfg.Tools))
-		for i, t := range cfg.Tools {
+		tools := plan.Tools()
+		tasks := make([]toolTask, len(tools))
+		for i, t := range tools {
 			tasks[i] = toolTask{name: t.DisplayName(), status: statusPending}
 		}
-		if len(tasks) > 0 {
-			tasks[0].status = statusInstalling
-		}
 
 		s := spinner.New()
 		s.Spinner = spinner.Dot
@@ -247,7 +330,8 @@ var installCmd = &cobra.Command{
 			indexPtr: &sharedIndex,
 			spinner:  s,
 			manager:  mgr,
-			tools:    cfg.Tools,
+			plan:     plan,
+			tools:    tools,
 		}
 
 		p := tea.NewProgram(m)
@@ -266,8 +350,106 @@ var installCmd = &cobra.Command{
 	},
 }
 
+// installEvent is a line of the JSON event stream of box install.
+type installEvent struct {
+	Event    string    `json:"event"` // started, output, finished, failed or skipped
+	Tool     string    `json:"tool"`
+	Line     string    `json:"line,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	Log      string    `json:"log,omitempty"` // Install log of a failed tool
+	Duration float64   `json:"duration_seconds,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// installJSON installs all tools and writes one JSON event per line to stdout.
+// It continues after failures, skips tools whose dependencies failed and
+// reports both in the returned error.
+func installJSON(mgr *installer.Manager, plan *installer.Plan) error {
+	enc := json.NewEncoder(os.Stdout)
+	emit := func(e installEvent) {
+		e.Time = time.Now()
+		_ = enc.Encode(e)
+	}
+
+	failed := 0
+	for tool, ok := plan.Next(); ok; tool, ok = plan.Next() {
+		name := tool.DisplayName()
+		out := &eventWriter{emit: func(line string) {
+			emit(installEvent{Event: "output", Tool: name, Line: line})
+		}}
+		mgr.Output = out
+
+		emit(installEvent{Event: "started", Tool: name})
+		start := time.Now()
+		err := mgr.Install(tool)
+		out.flush()
+		duration := time.Since(start).Seconds()
+		if err != nil {
+			failed++
+			log, _ := mgr.LatestLog(name)
+			emit(installEvent{Event: "failed", Tool: name, Error: err.Error(), Log: log, Duration: duration})
+		} else {
+			emit(installEvent{Event: "finished", Tool: name, Duration: duration})
+		}
+		for _, skipped := range plan.Finish(name, err) {
+			failed++
+			emit(installEvent{Event: "skipped", Tool: skipped.DisplayName(), Error: installer.DependencyError{Dependency: name}.Error()})
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to install %d tools", failed)
+	}
+	return nil
+}
+
+// eventWriter splits installer output into lines and passes them to emit.
+type eventWriter struct {
+	emit    func(line string)
+	partial string
+}
+
+func (w *eventWriter) Write(p []byte) (int, error) {
+	lines := strings.Split(w.partial+string(p), "\n")
+	w.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			w.emit(line)
+		}
+	}
+	return len(p), nil
+}
+
+// flush emits a trailing line without a newline.
+func (w *eventWriter) flush() {
+	if w.partial != "" {
+		w.emit(w.partial)
+		w.partial = ""
+	}
+}
+
+// newManager creates the installer for the project at root with the registry
+// mirrors and, if enabled, the shared store configured.
+func newManager(cfg *config.Config, root, path, tempDir string) (*installer.Manager, error) {
+	mgr := installer.New(root, tempDir, cfg.Env, cfg)
+	mgr.ConfigFile = path
+	mgr.Level = logLevel()
+	mgr.LogHandler = logFileHandler
+
+	var err error
+	if mgr.Registries, err = registries(cfg); err != nil {
+		return nil, err
+	}
+	if installer.UseSharedStore(cfg) {
+		if mgr.Store, err = store.Default(); err != nil {
+			return nil, err
+		}
+	}
+	return mgr, nil
+}
+
 func init() {
-	installCmd.Flags().BoolVarP(&nonInteractive, "non-interactive", "y", false, "Run in non-interactive mode (no TTY required)")
-	installCmd.Flags().StringVarP(&configFile, "file", "f", "box.yml", "Configuration file to use")
+	installCmd.Flags().BoolVarP(&nonInteractive, "non-interactive", "y", false, "Run in non-interactive mode (the default if stdout is not a terminal)")
+	installCmd.Flags().BoolVar(&offline, "offline", false, "Install from the artifacts downloaded by 'box fetch' without network access")
 	RootCmd.AddCommand(installCmd)
 }
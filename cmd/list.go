@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -12,20 +13,28 @@ import (
 	"github.com/sebakri/box/internal/installer"
 )
 
+var listWorkspace bool
+
 // listCmd represents the list command
 var listCmd = &cobra.Command{
-	Use:   "list",
-	Short: "Lists installed tools and their binaries",
+	Use:         "list",
+	Short:       "Lists installed tools and their binaries",
+	Annotations: supportsJSON,
 	RunE: func(_ *cobra.Command, _ []string) error {
-		configFile := "box.yml"
-		cfg, err := config.Load(configFile)
+		path, root, err := resolveConfig()
 		if err != nil {
-			return fmt.Errorf("failed to load %s: %w", configFile, err)
+			return err
 		}
 
-		cwd, err := os.Getwd()
+		var users map[string][]string
+		var cfg *config.Config
+		if listWorkspace {
+			cfg, root, users, err = loadWorkspaceUsage(path, root)
+		} else {
+			cfg, root, err = loadProject(path, root)
+		}
 		if err != nil {
-			return fmt.Errorf("failed to get current working directory: %w", err)
+			return fmt.Errorf("failed to load %s: %w", path, err)
 		}
 
 		// Create a specific temp directory for this session
@@ -37,24 +46,28 @@ var listCmd = &cobra.Command{
 			_ = os.RemoveAll(tempDir)
 		}()
 
-		mgr := installer.New(cwd, tempDir, cfg.Env, cfg)
+		mgr := installer.New(root, tempDir, cfg.Env, cfg)
 		manifest, err := mgr.LoadManifest()
 		if err != nil {
 			return fmt.Errorf("failed to load manifest: %w", err)
 		}
 
+		if jsonOutput() {
+			return printJSON(listEntries(cfg, manifest, users))
+		}
+
 		fmt.Println(titleStyle.Render("Installed tools:"))
 		for _, tool := range cfg.Tools {
-			fmt.Printf("• %s %s\n", toolStyle.Render(tool.DisplayName()), typeStyle.Render("("+tool.Type+")"))
+			fmt.Printf("• %s %s%s\n", toolStyle.Render(tool.DisplayName()), typeStyle.Render("("+tool.Type+")"), overlayLabel(cfg, tool.Origin))
+			if listWorkspace {
+				fmt.Printf("  %s %s\n", typeStyle.Render("needed by:"), strings.Join(users[tool.DisplayName()], ", "))
+			}
 
 			if info, ok := manifest.Tools[tool.DisplayName()]; ok {
-				binaries := []string{}
-				for _, file := range info.Files {
-					if strings.HasPrefix(file, ".box/bin/") {
-						binaries = append(binaries, filepath.Base(file))
-					}
+				if version := installedVersion(info); version != "" {
+					fmt.Printf("  %s %s\n", typeStyle.Render("version:"), version)
 				}
-
+				binaries := manifestBinaries(info)
 				if len(binaries) > 0 {
 					fmt.Printf("  %s %s\n", typeStyle.Render("binaries:"), binStyle.Render(strings.Join(binaries, ", ")))
 				}
@@ -64,6 +77,116 @@ var listCmd = &cobra.Command{
 	},
 }
 
+// listEntry is a tool in the JSON output of box list.
+type listEntry struct {
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`
+	Source    string     `json:"source"`
+	Version   string     `json:"version,omitempty"`
+	Resolved  string     `json:"resolved_version,omitempty"` // Version that was actually installed
+	Origin    string     `json:"origin,omitempty"`
+	Installed bool       `json:"installed"`
+	Binaries  []string   `json:"binaries"`
+	NeededBy  []string   `json:"needed_by,omitempty"`
+	Created   *time.Time `json:"installed_at,omitempty"`
+	Updated   *time.Time `json:"updated_at,omitempty"`
+}
+
+// listEntries combines the configured tools with their manifest entries.
+func listEntries(cfg *config.Config, manifest *installer.Manifest, users map[string][]string) []listEntry {
+	entries := make([]listEntry, 0, len(cfg.Tools))
+	for _, tool := range cfg.Tools {
+		entry := listEntry{
+			Name:     tool.DisplayName(),
+			Type:     tool.Type,
+			Source:   tool.Source.String(),
+			Version:  tool.Version,
+			Origin:   tool.Origin,
+			Binaries: []string{},
+			NeededBy: users[tool.DisplayName()],
+		}
+		if info, ok := manifest.Tools[tool.DisplayName()]; ok {
+			entry.Installed = true
+			entry.Resolved = info.ResolvedVersion
+			entry.Binaries = manifestBinaries(info)
+			entry.Created, entry.Updated = &info.Installed, &info.Updated
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// installedVersion describes the installed version of a tool, e.g. "v1.2.3 (latest)".
+func installedVersion(info installer.ToolManifest) string {
+	switch {
+	case info.ResolvedVersion == "":
+		return info.Version
+	case info.Version == "" || info.Version == info.ResolvedVersion:
+		return info.ResolvedVersion
+	default:
+		return fmt.Sprintf("%s (%s)", info.ResolvedVersion, info.Version)
+	}
+}
+
+// manifestBinaries returns the names of the files a tool installed into .box/bin.
+func manifestBinaries(info installer.ToolManifest) []string {
+	binaries := []string{}
+	for _, file := range info.Files {
+		if strings.HasPrefix(file, ".box/bin/") {
+			binaries = append(binaries, filepath.Base(file))
+		}
+	}
+	return binaries
+}
+
 func init() {
+	listCmd.Flags().BoolVar(&listWorkspace, "workspace", false, "List the tools of the whole workspace and which members need them")
 	RootCmd.AddCommand(listCmd)
 }
+
+// loadWorkspaceUsage loads the workspace that the configuration at path belongs
+// to. Besides the union of all tools it returns, per tool, the members that need it.
+func loadWorkspaceUsage(path, root string) (*config.Config, string, map[string][]string, error) {
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if cfg.Workspace == nil {
+		workspacePath, err := config.FindWorkspace(root)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		if workspacePath == "" {
+			return nil, "", nil, fmt.Errorf("%s is not part of a workspace", root)
+		}
+		if cfg, err = loadConfig(workspacePath); err != nil {
+			return nil, "", nil, err
+		}
+		root = filepath.Dir(workspacePath)
+	}
+
+	members, err := loadMembers(cfg, root)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	union, err := cfg.Union(members)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	users := make(map[string][]string)
+	shared := make(map[string]bool)
+	for _, t := range cfg.Tools {
+		// Tools of the workspace root are part of every member's view
+		users[t.DisplayName()] = []string{"all members"}
+		shared[t.DisplayName()] = true
+	}
+	for _, member := range members {
+		for _, t := range member.Config.Tools {
+			if !shared[t.DisplayName()] {
+				users[t.DisplayName()] = append(users[t.DisplayName()], member.Name)
+			}
+		}
+	}
+	return union, root, users, nil
+}
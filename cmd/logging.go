@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/sebakri/box/internal/logging"
+)
+
+var (
+	verbosity int
+	quiet     bool
+	logFile   string
+)
+
+// logFileHandler writes JSON records to --log-file, nil if not set.
+var logFileHandler slog.Handler
+
+// closeLogFile closes the file opened for --log-file.
+var closeLogFile = func() {}
+
+// logLevel returns the level of messages shown in the terminal.
+func logLevel() slog.Level {
+	return logging.LevelFor(verbosity, quiet)
+}
+
+// setupLogging configures the default logger from -v, --quiet and --log-file.
+func setupLogging() error {
+	if quiet && verbosity > 0 {
+		return fmt.Errorf("--quiet and --verbose cannot be used together")
+	}
+
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		closeLogFile = func() { _ = f.Close() }
+		// The log file always contains everything, independent of -v and --quiet
+		logFileHandler = slog.NewJSONHandler(f, &slog.HandlerOptions{Level: logging.LevelTrace})
+	}
+
+	slog.SetDefault(slog.New(logging.Multi(logging.NewHandler(os.Stderr, logLevel()), logFileHandler)))
+	return nil
+}
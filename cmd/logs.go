@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sebakri/box/internal/installer"
+
+	"github.com/spf13/cobra"
+)
+
+var logsList bool
+
+// logsCmd represents the logs command
+var logsCmd = &cobra.Command{
+	Use:   "logs <tool>",
+	Short: "Shows the latest install log of a tool",
+	Long: fmt.Sprintf(`Shows the full installer output of the most recent installation of a tool, as recorded in .box/logs.
+The %d most recent logs are kept per tool.`, installer.KeepLogs),
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		path, root, err := resolveProject()
+		if err != nil {
+			return err
+		}
+
+		cfg, root, err := loadOptionalProject(path, root)
+		if err != nil {
+			return err
+		}
+
+		mgr := installer.New(root, "", nil, cfg)
+		name := args[0]
+
+		if logsList {
+			logs, err := mgr.Logs(name)
+			if err != nil {
+				return err
+			}
+			if len(logs) == 0 {
+				return fmt.Errorf("no install logs found for %s", name)
+			}
+			for _, log := range logs {
+				fmt.Println(relativePath(log))
+			}
+			return nil
+		}
+
+		log, err := mgr.LatestLog(name)
+		if err != nil {
+			return err
+		}
+		if log == "" {
+			return fmt.Errorf("no install logs found for %s in %s", name, relativePath(filepath.Join(root, ".box", "logs")))
+		}
+		data, err := os.ReadFile(filepath.Clean(log))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", log, err)
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	},
+}
+
+func init() {
+	logsCmd.Flags().BoolVar(&logsList, "list", false, "List the kept logs of the tool instead of printing the latest one")
+	RootCmd.AddCommand(logsCmd)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// Output formats supported by the --output flag.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// jsonAnnotation marks commands that support --output json.
+const jsonAnnotation = "box/json-output"
+
+var outputFormat string
+
+// supportsJSON is the annotation of commands that support --output json.
+var supportsJSON = map[string]string{jsonAnnotation: "true"}
+
+// checkOutputFormat validates the --output flag for cmd.
+func checkOutputFormat(cmd *cobra.Command) error {
+	switch outputFormat {
+	case outputText:
+		return nil
+	case outputJSON:
+		if cmd.Annotations[jsonAnnotation] != "true" {
+			return fmt.Errorf("'%s' does not support --output json", cmd.CommandPath())
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q (supported: %s, %s)", outputFormat, outputText, outputJSON)
+	}
+}
+
+// jsonOutput reports whether machine-readable output was requested.
+func jsonOutput() bool {
+	return outputFormat == outputJSON
+}
+
+// printJSON writes v as indented JSON to stdout.
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// isTerminal reports whether f is connected to a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
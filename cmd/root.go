@@ -13,25 +13,45 @@ var RootCmd = &cobra.Command{
 	Short: "Minimalist project-local toolbox",
 	Long: `Box is a minimalist, project-local toolbox that keeps your development tools, 
 binaries, and environment variables neatly packed and isolated within your project.`,
+	// Parse root flags before the subcommand so they also work with 'box run'
+	TraverseChildren: true,
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		if err := checkOutputFormat(cmd); err != nil {
+			return err
+		}
+		if err := setupLogging(); err != nil {
+			return err
+		}
+		if projectDir != "" {
+			if err := os.Chdir(projectDir); err != nil {
+				return fmt.Errorf("failed to change to project directory: %w", err)
+			}
+		}
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the RootCmd.
 func Execute() {
-	if err := RootCmd.Execute(); err != nil {
-		fmt.Println(err)
+	err := RootCmd.Execute()
+	closeLogFile()
+	if err != nil {
+		if jsonOutput() {
+			// Keep stdout parseable
+			fmt.Fprintln(os.Stderr, err)
+		} else {
+			fmt.Println(err)
+		}
 		os.Exit(1)
 	}
 }
 
 func init() {
-	// Here you will define your flags and configuration settings.
-	// Cobra supports persistent flags, which, if defined here,
-	// will be global for your application.
-
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.box.yaml)")
-
-	// Cobra also supports local flags, which will only run
-	// when this action is called directly.
-	// rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	RootCmd.PersistentFlags().StringVarP(&projectDir, "project", "C", "", "Run as if box was started in this directory")
+	RootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, "Output format (text, json)")
+	RootCmd.PersistentFlags().StringVarP(&configFile, "file", "f", "", "Configuration file to use (default: nearest box.yml, box.yaml or .box.yml)")
+	RootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "Show debug messages, -vv also shows the full environment of commands")
+	RootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only show warnings and errors")
+	RootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "Append all log messages as JSON to this file")
 }
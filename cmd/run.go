@@ -2,11 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/installer"
 	"github.com/sebakri/box/internal/sandbox"
 
 	"github.com/spf13/cobra"
@@ -14,7 +17,7 @@ import (
 
 // runCmd represents the run command
 var runCmd = &cobra.Command{
-	Use:                "run <command> [args...]",
+	Use:                "run <command>[@<version>] [args...]",
 	Short:              "Execute a binary from the local .box/bin directory",
 	DisableFlagParsing: true,
 	SilenceUsage:       true,
@@ -26,25 +29,35 @@ var runCmd = &cobra.Command{
 		}
 		commandArgs := args[1:]
 
-		cwd, err := os.Getwd()
+		path, root, err := resolveProject()
 		if err != nil {
-			return fmt.Errorf("failed to get current working directory: %w", err)
+			return err
 		}
 
-		configFile := "box.yml"
-		cfg, err := config.Load(configFile)
+		// Without box.yml the binary still runs, but without custom env vars.
+		// A configuration that fails to load is an error, because its env and
+		// sandbox would be missing.
+		projectRoot := root
+		cfg, root, err := loadOptionalProject(path, root)
 		if err != nil {
-			// If box.yml is missing, we can still run if the binary exists,
-			// but we won't have custom env vars.
-			cfg = &config.Config{}
+			return err
 		}
 
-		boxDir := filepath.Join(cwd, ".box")
+		boxDir := filepath.Join(root, ".box")
 		binDir := filepath.Join(boxDir, "bin")
+		commandName, err = resolveBinary(cfg, binDir, commandName)
+		if err != nil {
+			return err
+		}
 		binaryPath := filepath.Join(binDir, commandName)
+		// Shims run through box run, which executes the binary they point to
+		if target := installer.ShimTarget(binaryPath); target != "" {
+			binaryPath = target
+		}
 
-		if _, err := os.Stat(binaryPath); os.IsNotExist(err) {
-			return fmt.Errorf("binary %s not found in .box/bin. Have you run 'box install'?", commandName)
+		// Workspace members only see the binaries of their own view of the workspace
+		if root != projectRoot && !inView(cfg, root, commandName) {
+			return fmt.Errorf("binary %s is not provided by any tool of this workspace member", commandName)
 		}
 
 		// Create a specific temp directory for this run
@@ -62,7 +75,7 @@ var runCmd = &cobra.Command{
 		tempCmd := exec.Command(binaryPath, commandArgs...)
 
 		if cfg.IsSandboxEnabled(commandName) {
-			finalCmdName, finalCmdArgs = sandbox.Apply(tempCmd, binaryPath, commandArgs, cwd, tempDir)
+			finalCmdName, finalCmdArgs = sandbox.Apply(tempCmd, binaryPath, commandArgs, root, tempDir, slog.Default())
 		}
 
 		//nolint:gosec
@@ -112,3 +125,50 @@ var runCmd = &cobra.Command{
 func init() {
 	RootCmd.AddCommand(runCmd)
 }
+
+// resolveBinary returns the name in .box/bin of a command. A command that is
+// not in .box/bin is looked up among the versions of tools with a
+// bin_name_template, optionally selected with "name@version".
+func resolveBinary(cfg *config.Config, binDir, name string) (string, error) {
+	if _, err := os.Stat(filepath.Join(binDir, name)); err == nil {
+		return name, nil
+	}
+
+	base, version := name, ""
+	if i := strings.LastIndex(name, "@"); i > 0 {
+		base, version = name[:i], name[i+1:]
+	}
+	var variants []string
+	for _, v := range cfg.BinaryVariants(base, version) {
+		if _, err := os.Stat(filepath.Join(binDir, v)); err == nil {
+			variants = append(variants, v)
+		}
+	}
+
+	switch len(variants) {
+	case 0:
+		return "", fmt.Errorf("binary %s not found in .box/bin. Have you run 'box install'?", name)
+	case 1:
+		return variants[0], nil
+	default:
+		return "", fmt.Errorf("binary %s is ambiguous, it is installed as %s; run 'box run %s@<version>' to choose one", name, strings.Join(variants, ", "), base)
+	}
+}
+
+// inView reports whether the binary was installed by one of the tools in cfg.
+func inView(cfg *config.Config, root, binary string) bool {
+	manifest, err := installer.New(root, "", nil, cfg).LoadManifest()
+	if err != nil {
+		return false
+	}
+
+	binPath := filepath.Join(".box", "bin", binary)
+	for _, tool := range cfg.Tools {
+		for _, file := range manifest.Tools[tool.DisplayName()].Files {
+			if file == binPath || file == binPath+".exe" {
+				return true
+			}
+		}
+	}
+	return false
+}
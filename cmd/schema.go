@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/installer"
+)
+
+// schemaCmd represents the schema command
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Prints a JSON Schema for box.yml",
+	Long: `Prints a JSON Schema for box.yml that editors can use for autocompletion and validation, e.g.:
+
+  box schema > box.schema.json
+
+and reference it in box.yml with:
+
+  # yaml-language-server: $schema=box.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		data, err := config.Schema(installer.SupportedTypeNames())
+		if err != nil {
+			return fmt.Errorf("failed to generate schema: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(schemaCmd)
+}
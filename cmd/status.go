@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sebakri/box/internal/installer"
+
+	"github.com/spf13/cobra"
+)
+
+var statusJSON bool
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Compares the configured tools with the installed ones",
+	Long: `Compares each tool in box.yml with the manifest in .box and reports whether it is installed,
+not installed, changed (different type, source, version, args or binaries), has a broken link in
+.box/bin, or is orphaned (installed but no longer configured).
+Exits with a non-zero status unless every tool is installed, which makes it suitable for CI and
+pre-commit checks.`,
+	Args:        cobra.NoArgs,
+	Annotations: supportsJSON,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		path, root, err := resolveConfig()
+		if err != nil {
+			return err
+		}
+
+		projectRoot := root
+		cfg, root, err := loadProject(path, root)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+
+		mgr := installer.New(root, "", cfg.Env, cfg)
+		statuses, err := mgr.Status(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to load manifest: %w", err)
+		}
+
+		// Inside a workspace member, tools of other members are not orphaned
+		if root != projectRoot {
+			union, _, _, err := loadWorkspaceUsage(path, projectRoot)
+			if err != nil {
+				return err
+			}
+			workspaceTools := make(map[string]bool)
+			for _, t := range union.Tools {
+				workspaceTools[t.DisplayName()] = true
+			}
+			filtered := statuses[:0]
+			for _, s := range statuses {
+				if s.State != installer.StateOrphaned || !workspaceTools[s.Name] {
+					filtered = append(filtered, s)
+				}
+			}
+			statuses = filtered
+		}
+
+		drift := 0
+		for _, s := range statuses {
+			if s.State != installer.StateInstalled {
+				drift++
+			}
+		}
+
+		if statusJSON || jsonOutput() {
+			if err := printJSON(struct {
+				Tools []installer.ToolStatus `json:"tools"`
+				Clean bool                   `json:"clean"`
+			}{statuses, drift == 0}); err != nil {
+				return err
+			}
+		} else {
+			printStatus(statuses)
+		}
+
+		if drift > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d tools are not installed as configured", drift)
+		}
+		return nil
+	},
+}
+
+func printStatus(statuses []installer.ToolStatus) {
+	fmt.Println(titleStyle.Render("Tool status:"))
+	if len(statuses) == 0 {
+		fmt.Println("No tools configured.")
+		return
+	}
+	for _, s := range statuses {
+		mark := successStyle.Render("✓")
+		if s.State != installer.StateInstalled {
+			mark = warnStyle.Render("✗")
+		}
+		fmt.Printf("%s %s %s %s\n", mark, toolStyle.Render(s.Name), typeStyle.Render("("+s.Type+")"), string(s.State))
+		for _, change := range s.Changes {
+			fmt.Printf("  %s\n", typeStyle.Render(change))
+		}
+		if len(s.Broken) > 0 {
+			fmt.Printf("  %s %s\n", typeStyle.Render("broken:"), binStyle.Render(strings.Join(s.Broken, ", ")))
+		}
+	}
+}
+
+func init() {
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Print the status as JSON (same as --output json)")
+	RootCmd.AddCommand(statusCmd)
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/installer"
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Validates box.yml",
+	Long: `Validates box.yml, the files it extends and its overlays strictly: unknown keys, unsupported tool types, go versions without a 'v' prefix,
+duplicate tools, binaries provided by more than one tool and empty sources are reported with their file:line:column.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var path string
+		if len(args) > 0 {
+			path = args[0]
+		} else {
+			var err error
+			if path, _, err = resolveConfig(); err != nil {
+				return err
+			}
+			path = relativePath(path)
+		}
+
+		issues, err := config.Validate(path, installer.SupportedTypeNames())
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if len(issues) == 0 {
+			fmt.Printf("%s %s is valid\n", successStyle.Render("✅"), path)
+			return nil
+		}
+
+		for _, issue := range issues {
+			fmt.Printf("%s %s\n", warnStyle.Render("✗"), issue)
+		}
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%s has %d problem(s)", path, len(issues))
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(validateCmd)
+}
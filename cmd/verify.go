@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/installer"
+
+	"github.com/spf13/cobra"
+)
+
+var verifyRepair bool
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Checks the installed files against the checksums recorded in the manifest",
+	Long: `Recomputes the checksum, size and mode of every file recorded in .box/manifest.json and
+reports modified and missing files per tool, as well as files in .box/bin that no tool installed.
+Exits with a non-zero status if anything differs. With --repair, only the affected tools are
+reinstalled and unexpected files are removed from .box/bin.`,
+	Args:        cobra.NoArgs,
+	Annotations: supportsJSON,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		path, root, err := resolveConfig()
+		if err != nil {
+			return err
+		}
+
+		cfg, root, err := loadProject(path, root)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+
+		tempDir, err := os.MkdirTemp("", "box-verify-*")
+		if err != nil {
+			return fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		defer func() {
+			_ = os.RemoveAll(tempDir)
+		}()
+
+		mgr, err := newManager(cfg, root, path, tempDir)
+		if err != nil {
+			return err
+		}
+
+		report, err := mgr.Verify()
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			// Installer output would mix with the JSON report
+			mgr.Output = io.Discard
+		} else {
+			printVerifyReport(report)
+		}
+
+		if !report.Clean() && verifyRepair {
+			if !jsonOutput() {
+				fmt.Println()
+			}
+			if err := repair(mgr, cfg, report); err != nil {
+				return err
+			}
+			if report, err = mgr.Verify(); err != nil {
+				return err
+			}
+			if !jsonOutput() {
+				fmt.Println()
+				printVerifyReport(report)
+			}
+		}
+
+		if jsonOutput() {
+			if err := printJSON(struct {
+				*installer.VerifyReport
+				Clean bool `json:"clean"`
+			}{report, report.Clean()}); err != nil {
+				return err
+			}
+		}
+
+		if !report.Clean() {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("installed files differ from the manifest")
+		}
+		return nil
+	},
+}
+
+func printVerifyReport(report *installer.VerifyReport) {
+	if report.Clean() {
+		fmt.Println(successStyle.Render("✅ All installed files match the manifest."))
+		return
+	}
+
+	for _, tool := range report.Tools {
+		fmt.Printf("%s %s\n", warnStyle.Render("✗"), toolStyle.Render(tool.Name))
+		for _, file := range tool.Modified {
+			fmt.Printf("  %s %s\n", typeStyle.Render("modified:"), file)
+		}
+		for _, file := range tool.Missing {
+			fmt.Printf("  %s %s\n", typeStyle.Render("missing: "), file)
+		}
+		if tool.Unverified {
+			fmt.Printf("  %s\n", typeStyle.Render("installed without checksums, only missing files are detected"))
+		}
+	}
+	if len(report.Unexpected) > 0 {
+		fmt.Printf("%s %s\n", warnStyle.Render("✗"), "unexpected files")
+		for _, file := range report.Unexpected {
+			fmt.Printf("  %s\n", file)
+		}
+	}
+}
+
+// repair reinstalls the tools with drift and removes unexpected files.
+func repair(mgr *installer.Manager, cfg *config.Config, report *installer.VerifyReport) error {
+	for _, drift := range report.Tools {
+		var tool *config.Tool
+		for i := range cfg.Tools {
+			if cfg.Tools[i].DisplayName() == drift.Name {
+				tool = &cfg.Tools[i]
+				break
+			}
+		}
+		if tool == nil {
+			if !jsonOutput() {
+				fmt.Printf("%s Skipped %s: it is not defined in the configuration\n", warnStyle.Render("⚠️"), drift.Name)
+			}
+			continue
+		}
+
+		if !jsonOutput() {
+			fmt.Printf("• Reinstalling %s...\n", drift.Name)
+		}
+		if err := mgr.Uninstall(drift.Name); err != nil {
+			return fmt.Errorf("failed to uninstall %s: %w", drift.Name, err)
+		}
+		if err := mgr.Install(*tool); err != nil {
+			return fmt.Errorf("failed to reinstall %s: %w", drift.Name, err)
+		}
+	}
+	return mgr.RemoveUnexpected(report.Unexpected)
+}
+
+func init() {
+	verifyCmd.Flags().BoolVar(&verifyRepair, "repair", false, "Reinstall tools with modified or missing files and remove unexpected files")
+	RootCmd.AddCommand(verifyCmd)
+}
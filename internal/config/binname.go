@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// BinName is the data of a bin_name_template.
+type BinName struct {
+	Name    string // Name of the binary as installed by the tool
+	Version string // Configured version of the tool
+}
+
+// LinkName returns the name under which a binary of the tool is linked into
+// .box/bin. A rename takes precedence over the bin_name_template, and without
+// either it is the binary name.
+func (t Tool) LinkName(binary string) (string, error) {
+	if name, ok := t.Rename[binary]; ok {
+		if !validBinName(name) {
+			return "", fmt.Errorf("rename of %s produces the invalid binary name %q", binary, name)
+		}
+		return name, nil
+	}
+	if t.BinNameTemplate == "" {
+		return binary, nil
+	}
+
+	tmpl, err := template.New("bin_name_template").Option("missingkey=error").Parse(t.BinNameTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid bin_name_template: %w", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, BinName{Name: binary, Version: t.Version}); err != nil {
+		return "", fmt.Errorf("invalid bin_name_template: %w", err)
+	}
+
+	name := b.String()
+	if !validBinName(name) {
+		return "", fmt.Errorf("bin_name_template %q produces the invalid binary name %q", t.BinNameTemplate, name)
+	}
+	return name, nil
+}
+
+// validBinName reports whether name can be used as a file name in .box/bin.
+func validBinName(name string) bool {
+	return name != "" && name != "." && name != ".." && name == filepath.Base(name) && !strings.ContainsAny(name, `/\`)
+}
+
+// LinkNames returns the names of the tool's binaries in .box/bin. Scripts
+// without explicit binaries have none, and invalid names are left out.
+func (t Tool) LinkNames() []string {
+	if t.Type == "script" && len(t.Binaries) == 0 {
+		return nil
+	}
+	var names []string
+	for _, b := range t.binaryNames() {
+		if name, err := t.LinkName(b); err == nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// binaryNames returns the explicit binaries of the tool, or else the name
+// detected from its source.
+func (t Tool) binaryNames() []string {
+	if len(t.Binaries) > 0 {
+		return t.Binaries
+	}
+	return []string{t.detectBinaryName()}
+}
+
+// BinaryVariants returns the names in .box/bin of a binary that is provided
+// by tools with a bin_name_template. Unless version is empty, only tools
+// whose version matches it, with or without a "v" prefix, are included.
+func (c *Config) BinaryVariants(binary, version string) []string {
+	var names []string
+	for _, t := range c.Tools {
+		if t.BinNameTemplate == "" {
+			continue
+		}
+		if version != "" && strings.TrimPrefix(t.Version, "v") != strings.TrimPrefix(version, "v") {
+			continue
+		}
+		for _, b := range t.binaryNames() {
+			if b != binary {
+				continue
+			}
+			if name, err := t.LinkName(b); err == nil {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
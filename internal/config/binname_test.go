@@ -0,0 +1,160 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLinkName(t *testing.T) {
+	tool := Tool{Type: "go", Source: Source{"google.golang.org/protobuf/cmd/protoc-gen-go"}, Version: "v1.28.1", BinNameTemplate: "{{.Name}}@{{.Version}}"}
+
+	name, err := tool.LinkName("protoc-gen-go")
+	if err != nil || name != "protoc-gen-go@v1.28.1" {
+		t.Errorf("expected protoc-gen-go@v1.28.1, got %q (%v)", name, err)
+	}
+	if got := tool.DisplayName(); got != "google.golang.org/protobuf/cmd/protoc-gen-go@v1.28.1" {
+		t.Errorf("expected the version in the display name, got %q", got)
+	}
+	if got := tool.LinkNames(); !reflect.DeepEqual(got, []string{"protoc-gen-go@v1.28.1"}) {
+		t.Errorf("unexpected link names %v", got)
+	}
+
+	for _, tmpl := range []string{"{{.Name", "{{.Missing}}", "bin/{{.Name}}", "{{if false}}x{{end}}"} {
+		tool.BinNameTemplate = tmpl
+		if _, err := tool.LinkName("protoc-gen-go"); err == nil {
+			t.Errorf("expected an error for template %q", tmpl)
+		}
+	}
+}
+
+func TestBinaryVariants(t *testing.T) {
+	cfg := &Config{Tools: []Tool{
+		{Type: "go", Source: Source{"google.golang.org/protobuf/cmd/protoc-gen-go"}, Version: "v1.34.2", BinNameTemplate: "{{.Name}}@{{.Version}}"},
+		{Type: "go", Source: Source{"google.golang.org/protobuf/cmd/protoc-gen-go"}, Version: "v1.28.1", BinNameTemplate: "{{.Name}}-legacy"},
+		{Type: "go", Source: Source{"github.com/go-task/task/v3/cmd/task"}},
+	}}
+
+	if got := cfg.BinaryVariants("protoc-gen-go", ""); !reflect.DeepEqual(got, []string{"protoc-gen-go-legacy", "protoc-gen-go@v1.34.2"}) {
+		t.Errorf("unexpected variants %v", got)
+	}
+	if got := cfg.BinaryVariants("protoc-gen-go", "1.28.1"); !reflect.DeepEqual(got, []string{"protoc-gen-go-legacy"}) {
+		t.Errorf("expected the version to match without the v prefix, got %v", got)
+	}
+	if got := cfg.BinaryVariants("task", ""); len(got) != 0 {
+		t.Errorf("expected no variants of a tool without template, got %v", got)
+	}
+
+	if tool := cfg.FindToolForBinary("protoc-gen-go-legacy"); tool == nil || tool.Version != "v1.28.1" {
+		t.Errorf("expected the legacy tool for its renamed binary, got %+v", tool)
+	}
+	if tool := cfg.FindToolForBinary("protoc-gen-go"); tool != nil {
+		t.Errorf("expected no tool for the original name, got %+v", tool)
+	}
+}
+
+func TestValidateBinNameTemplate(t *testing.T) {
+	content := `tools:
+  - type: go
+    source: google.golang.org/protobuf/cmd/protoc-gen-go
+    version: v1.34.2
+  - type: go
+    source: google.golang.org/protobuf/cmd/protoc-gen-go
+    version: v1.28.1
+    bin_name_template: "{{.Name}}@{{.Version}}"
+  - type: go
+    source: google.golang.org/protobuf/cmd/protoc-gen-go
+    version: v1.20.0
+  - type: go
+    source: example.com/tool
+    bin_name_template: "{{.Nam}}"
+`
+	path := filepath.Join(t.TempDir(), "box.yml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := Validate(path, testTypes)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	expected := []string{
+		path + `:10:13: duplicate tool "google.golang.org/protobuf/cmd/protoc-gen-go"`,
+		path + `:14:24: invalid bin_name_template`,
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues, got %d: %v", len(expected), len(issues), issues)
+	}
+	for i, want := range expected {
+		if !strings.HasPrefix(issues[i].String(), want) {
+			t.Errorf("Issue %d: expected prefix %q, got %q", i, want, issues[i].String())
+		}
+	}
+}
+
+func TestRename(t *testing.T) {
+	tool := Tool{
+		Type:            "go",
+		Source:          Source{"github.com/golangci/golangci-lint/v2/cmd/golangci-lint"},
+		Version:         "v2.9.0",
+		BinNameTemplate: "{{.Name}}@{{.Version}}",
+		Rename:          map[string]string{"golangci-lint": "lint"},
+	}
+	if got := tool.LinkNames(); !reflect.DeepEqual(got, []string{"lint"}) {
+		t.Errorf("expected the rename to take precedence over the template, got %v", got)
+	}
+	cfg := &Config{Tools: []Tool{tool}}
+	if found := cfg.FindToolForBinary("lint"); found == nil {
+		t.Error("expected the tool to be found by its new name")
+	}
+
+	tool.Rename["golangci-lint"] = "../lint"
+	if _, err := tool.LinkName("golangci-lint"); err == nil {
+		t.Error("expected an error for a rename to a path")
+	}
+}
+
+func TestValidateRename(t *testing.T) {
+	content := `tools:
+  - type: go
+    source: github.com/go-task/task/v3/cmd/task
+  - type: npm
+    source: task
+    rename:
+      task: npm-task
+  - type: go
+    source: example.com/tool
+    rename:
+      other: x
+      tool: ""
+  - type: script
+    source: ./install.sh
+    rename:
+      anything: fine
+`
+	path := filepath.Join(t.TempDir(), "box.yml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := Validate(path, testTypes)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	expected := []string{
+		path + `:11:14: rename of unknown binary "other"`,
+		path + `:12:13: rename of tool produces the invalid binary name ""`,
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues, got %d: %v", len(expected), len(issues), issues)
+	}
+	for i, want := range expected {
+		if !strings.HasPrefix(issues[i].String(), want) {
+			t.Errorf("Issue %d: expected prefix %q, got %q", i, want, issues[i].String())
+		}
+	}
+}
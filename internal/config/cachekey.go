@@ -0,0 +1,90 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+)
+
+// CacheKey computes a stable key for caching the .box directory in CI. It
+// hashes the Inputs of the configuration file at path and is prefixed with the
+// platform since installed binaries are platform specific.
+func CacheKey(path string) (string, error) {
+	files, err := Inputs(path)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	dir := filepath.Dir(path)
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return "", err
+		}
+		// Include the file name so moving content between files changes the key
+		name, err := filepath.Rel(dir, file)
+		if err != nil {
+			name = filepath.Base(file)
+		}
+		_, _ = io.WriteString(h, filepath.ToSlash(name)+"\x00")
+		_, _ = h.Write(data)
+		_, _ = io.WriteString(h, "\x00")
+	}
+
+	return fmt.Sprintf("box-%s-%s-%s", runtime.GOOS, runtime.GOARCH, hex.EncodeToString(h.Sum(nil))), nil
+}
+
+// Inputs returns the committed files that determine the tools of the
+// configuration at path: the file itself, the files it extends, go.mod and
+// go.sum with go_mod_tools, and the same files of workspace members. Overlays
+// are left out, as they differ between machines.
+func Inputs(path string) ([]string, error) {
+	cfg, err := LoadCommitted(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := inputs(path, cfg)
+	if cfg.Workspace != nil {
+		members, err := cfg.Workspace.MemberPaths(filepath.Dir(path))
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			memberCfg, err := LoadCommitted(member)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load workspace member %s: %w", member, err)
+			}
+			files = append(files, inputs(member, memberCfg)...)
+		}
+	}
+
+	// A file extended by several configurations is listed once
+	unique := files[:0]
+	for _, file := range files {
+		if !slices.Contains(unique, file) {
+			unique = append(unique, file)
+		}
+	}
+	return unique, nil
+}
+
+// inputs returns the files of one configuration for Inputs.
+func inputs(path string, cfg *Config) []string {
+	files := slices.Clone(cfg.Files)
+	if cfg.GoModTools {
+		for _, name := range []string{"go.mod", "go.sum"} {
+			file := filepath.Join(filepath.Dir(path), name)
+			if _, err := os.Stat(file); err == nil {
+				files = append(files, file)
+			}
+		}
+	}
+	return files
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCacheKey(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "box.yml")
+	if err := os.WriteFile(configPath, []byte("tools: []\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := CacheKey(configPath)
+	if err != nil {
+		t.Fatalf("CacheKey failed: %v", err)
+	}
+	if !strings.HasPrefix(key, "box-"+runtime.GOOS+"-"+runtime.GOARCH+"-") {
+		t.Errorf("unexpected key prefix: %s", key)
+	}
+
+	again, _ := CacheKey(configPath)
+	if again != key {
+		t.Errorf("expected stable key, got %s and %s", key, again)
+	}
+
+	// go.mod is an input with go_mod_tools
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module a\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if withGoMod, _ := CacheKey(configPath); withGoMod != key {
+		t.Error("expected go.mod not to change the key without go_mod_tools")
+	}
+	if err := os.WriteFile(configPath, []byte("go_mod_tools: true\ntools: []\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	withGoModTools, _ := CacheKey(configPath)
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module a\n\ntool example.com/b\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	withTool, err := CacheKey(configPath)
+	if err != nil {
+		t.Fatalf("CacheKey failed: %v", err)
+	}
+	if withTool == withGoModTools {
+		t.Error("expected the key to change with go.mod when go_mod_tools is set")
+	}
+
+	// Overlays are private to a machine and must not change the key
+	if err := os.WriteFile(filepath.Join(tmpDir, LocalFile), []byte("tools: []\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	overlay := filepath.Join(t.TempDir(), "overlay.yml")
+	if err := os.WriteFile(overlay, []byte("env:\n  A: b\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(OverlayEnv, overlay)
+	if withOverlays, err := CacheKey(configPath); err != nil || withOverlays != withTool {
+		t.Errorf("expected overlays not to change the key, got %s and %s (%v)", withTool, withOverlays, err)
+	}
+
+	if _, err := CacheKey(filepath.Join(tmpDir, "missing.yml")); err == nil {
+		t.Error("expected error for missing configuration file")
+	}
+}
+
+func TestCacheKeyWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"box.yml":                "workspace:\n  members: [services/*]\n",
+		"services/api/box.yml":   "extends: [tools.yml]\n",
+		"services/api/tools.yml": "tools: []\n",
+	})
+	configPath := filepath.Join(dir, "box.yml")
+
+	inputs, err := Inputs(configPath)
+	if err != nil {
+		t.Fatalf("Inputs failed: %v", err)
+	}
+	if len(inputs) != 3 {
+		t.Errorf("expected the root, member and extended files as inputs, got %v", inputs)
+	}
+
+	key, err := CacheKey(configPath)
+	if err != nil {
+		t.Fatalf("CacheKey failed: %v", err)
+	}
+	writeFiles(t, dir, map[string]string{"services/api/tools.yml": "tools:\n  - type: go\n    source: example.com/a\n"})
+	if changed, _ := CacheKey(configPath); changed == key {
+		t.Error("expected the key to change with a file extended by a workspace member")
+	}
+}
@@ -52,12 +52,26 @@ func (c *Config) IsSandboxEnabled(binaryName string) bool {
 
 // Tool defines a single tool to be installed by box.
 type Tool struct {
-	Type     string   `yaml:"type"`               // "go", "npm", "cargo", "uv", "gem", "script"
-	Source   Source   `yaml:"source"`             // Package path or script command
-	Alias    string   `yaml:"alias,omitempty"`    // Optional alias for display
-	Version  string   `yaml:"version,omitempty"`  // Optional version (e.g., "latest", "0.1.0")
-	Binaries []string `yaml:"binaries,omitempty"` // Optional explicit list of binaries
-	Args     []string `yaml:"args,omitempty"`
+	Type            string            `yaml:"type"`               // "go", "npm", "cargo", "uv", "gem", "script"
+	Source          Source            `yaml:"source"`             // Package path or script command
+	Alias           string            `yaml:"alias,omitempty"`    // Optional alias for display
+	Version         string            `yaml:"version,omitempty"`  // Optional version (e.g., "latest", "0.1.0")
+	Binaries        []string          `yaml:"binaries,omitempty"` // Optional explicit list of binaries
+	Args            []string          `yaml:"args,omitempty"`
+	DependsOn       []string          `yaml:"depends_on,omitempty"`        // Display names of tools installed before this one
+	PostInstall     Source            `yaml:"post_install,omitempty"`      // Commands run after the tool is installed
+	Verify          *Verify           `yaml:"verify,omitempty"`            // Check that the installed tool works
+	VersionCommand  string            `yaml:"version_command,omitempty"`   // Command printing the installed version
+	BinNameTemplate string            `yaml:"bin_name_template,omitempty"` // Name of the binaries in .box/bin, e.g. "{{.Name}}@{{.Version}}"
+	Rename          map[string]string `yaml:"rename,omitempty"`            // Names under which binaries are linked into .box/bin
+	Shim            bool              `yaml:"shim,omitempty"`              // Link the binaries as shims that run them with 'box run'
+	Origin          string            `yaml:"-"`                           // File the tool was defined in
+}
+
+// Verify is a command that checks an installed tool, e.g. by printing its version.
+type Verify struct {
+	Command string `yaml:"command"`
+	Expect  string `yaml:"expect,omitempty"` // Regular expression the output must match
 }
 
 // IsSandboxEnabled returns true if sandboxing is enabled for this tool.
@@ -70,15 +84,9 @@ func (t Tool) IsSandboxEnabled() bool {
 func (c *Config) FindToolForBinary(binaryName string) *Tool {
 	for i := range c.Tools {
 		t := &c.Tools[i]
-		// Check explicit binaries
-		for _, b := range t.Binaries {
-			if b == binaryName {
-				return t
-			}
-		}
-		// If no explicit binaries, try to match the source-based name
-		if len(t.Binaries) == 0 {
-			if t.detectBinaryName() == binaryName {
+		// Explicit binaries, or else the source-based name
+		for _, b := range t.binaryNames() {
+			if name, err := t.LinkName(b); err == nil && name == binaryName {
 				return t
 			}
 		}
@@ -124,33 +132,85 @@ func isDigit(s string) bool {
 	return true
 }
 
-// DisplayName returns a human-readable name for the tool.
+// DisplayName returns a human-readable name for the tool. Tools with a
+// bin_name_template can be installed in several versions side by side, so
+// their name includes the version.
 func (t Tool) DisplayName() string {
 	if t.Alias != "" {
 		return t.Alias
 	}
+	if t.BinNameTemplate != "" && t.Version != "" {
+		return t.Source.String() + "@" + t.Version
+	}
 	return t.Source.String()
 }
 
 // Config represents the top-level box configuration.
 type Config struct {
-	Tools []Tool            `yaml:"tools"`
-	Env   map[string]string `yaml:"env,omitempty"`
+	Tools       []Tool              `yaml:"tools"`
+	Env         map[string]string   `yaml:"env,omitempty"`
+	GoModTools  bool                `yaml:"go_mod_tools,omitempty"` // Sync go tools from the tool directives in go.mod
+	Extends     []string            `yaml:"extends,omitempty"`      // Files (or globs) merged before this file
+	Remove      []string            `yaml:"remove,omitempty"`       // Display names of inherited tools to drop
+	Workspace   *Workspace          `yaml:"workspace,omitempty"`    // Member projects sharing this project's .box
+	SharedStore bool                `yaml:"shared_store,omitempty"` // Install tools into the shared store and link them
+	Registries  map[string]Registry `yaml:"registries,omitempty"`   // Package registry mirrors keyed by tool type
+	Shims       bool                `yaml:"shims,omitempty"`        // Link the binaries of all tools as shims
+
+	EnvOrigin map[string]string `yaml:"-"` // File each env variable was defined in
+	Files     []string          `yaml:"-"` // All files merged into this configuration, in merge order
+	Overlays  []string          `yaml:"-"` // Overlay files applied on top of the configuration
 }
 
-// Load loads the configuration from the given path.
+// LocalFile is the name of the optional, git-ignored overlay next to box.yml.
+const LocalFile = "box.local.yml"
+
+// OverlayEnv names additional overlay files, separated by the OS path list separator.
+const OverlayEnv = "BOX_CONFIG_OVERLAY"
+
+// Load loads the configuration from the given path and resolves the files it extends.
+// Afterwards box.local.yml next to it and the files in BOX_CONFIG_OVERLAY are applied on top.
 func Load(path string) (*Config, error) {
-	data, err := os.ReadFile(filepath.Clean(path))
+	cfg, err := load(path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	local := filepath.Join(filepath.Dir(path), LocalFile)
+	if _, err := os.Stat(local); err != nil && !os.IsNotExist(err) {
 		return nil, err
+	} else if err == nil {
+		if err := cfg.applyOverlay(local); err != nil {
+			return nil, err
+		}
 	}
 
-	return &cfg, nil
+	for _, overlay := range filepath.SplitList(os.Getenv(OverlayEnv)) {
+		if overlay == "" {
+			continue
+		}
+		if err := cfg.applyOverlay(overlay); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// LoadCommitted loads the configuration from the given path and resolves the
+// files it extends, without box.local.yml and BOX_CONFIG_OVERLAY. The result
+// is the same on every machine checking out the project.
+func LoadCommitted(path string) (*Config, error) {
+	return load(path, nil)
+}
+
+// FromOverlay reports whether a tool or env variable defined in origin came from an overlay.
+func (c *Config) FromOverlay(origin string) bool {
+	for _, o := range c.Overlays {
+		if o == origin {
+			return true
+		}
+	}
+	return false
 }
 
 // Save writes the configuration to the given path.
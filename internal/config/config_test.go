@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -169,3 +171,116 @@ func TestIsSandboxEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestAppendTools(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "box.yml")
+	content := []byte(`# Project tools
+tools:
+  # Task runner
+  - type: go
+    source: github.com/go-task/task/v3/cmd/task
+    version: v3.40.0
+env:
+  KEY: value
+`)
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	added, err := AppendTools(path, []Tool{
+		{Type: "go", Source: Source{"github.com/go-task/task/v3/cmd/task"}, Version: "v3.41.0"},
+		{Type: "npm", Source: Source{"cowsay"}},
+	})
+	if err != nil {
+		t.Fatalf("AppendTools failed: %v", err)
+	}
+	if len(added) != 1 || added[0].Source.String() != "cowsay" {
+		t.Errorf("expected only cowsay to be added, got %+v", added)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, comment := range []string{"# Project tools", "# Task runner"} {
+		if !strings.Contains(string(data), comment) {
+			t.Errorf("expected comment %q to be preserved:\n%s", comment, data)
+		}
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Tools) != 2 || cfg.Tools[1].Source.String() != "cowsay" {
+		t.Errorf("unexpected tools after append: %+v", cfg.Tools)
+	}
+	if cfg.Env["KEY"] != "value" {
+		t.Errorf("expected env to be preserved, got %v", cfg.Env)
+	}
+}
+
+func TestAppendToolsCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "box.yml")
+
+	if _, err := AppendTools(path, []Tool{{Type: "uv", Source: Source{"ruff"}}}); err != nil {
+		t.Fatalf("AppendTools failed: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Tools) != 1 || cfg.Tools[0].Source.String() != "ruff" {
+		t.Errorf("unexpected tools: %+v", cfg.Tools)
+	}
+}
+
+func TestLoadOverlays(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"box.yml": `
+tools:
+  - type: go
+    source: example.com/tool
+  - type: uv
+    source: ruff
+env:
+  KEY: value
+  DEBUG: "0"
+`,
+		LocalFile: `
+remove: [ruff]
+tools:
+  - type: npm
+    source: cowsay
+env:
+  DEBUG: "1"
+`,
+		"extra.yml": "env:\n  EXTRA: yes\n",
+	})
+	t.Setenv(OverlayEnv, filepath.Join(dir, "extra.yml"))
+
+	cfg, err := Load(filepath.Join(dir, "box.yml"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(cfg.Tools) != 2 || cfg.Tools[1].DisplayName() != "cowsay" {
+		t.Fatalf("unexpected tools: %+v", cfg.Tools)
+	}
+	if cfg.FromOverlay(cfg.Tools[0].Origin) || !cfg.FromOverlay(cfg.Tools[1].Origin) {
+		t.Errorf("expected only cowsay to come from an overlay")
+	}
+	if cfg.Env["DEBUG"] != "1" || cfg.Env["KEY"] != "value" || cfg.Env["EXTRA"] != "yes" {
+		t.Errorf("unexpected env: %v", cfg.Env)
+	}
+	if filepath.Base(cfg.EnvOrigin["DEBUG"]) != LocalFile {
+		t.Errorf("expected DEBUG to come from %s, got %s", LocalFile, cfg.EnvOrigin["DEBUG"])
+	}
+
+	t.Setenv(OverlayEnv, filepath.Join(dir, "missing.yml"))
+	if _, err := Load(filepath.Join(dir, "box.yml")); err == nil {
+		t.Error("expected error for missing overlay")
+	}
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InstallOrder returns the tools ordered so that every tool comes after the
+// tools it depends on. Otherwise the order of tools is kept. It fails if a
+// tool depends on an unknown tool or the dependencies form a cycle.
+func InstallOrder(tools []Tool) ([]Tool, error) {
+	index := make(map[string]int, len(tools))
+	for i, t := range tools {
+		index[t.DisplayName()] = i
+	}
+	for _, t := range tools {
+		for _, dep := range t.DependsOn {
+			if _, ok := index[dep]; !ok {
+				return nil, fmt.Errorf("tool %q depends on unknown tool %q", t.DisplayName(), dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(tools))
+	ordered := make([]Tool, 0, len(tools))
+	var path []string
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		name := tools[i].DisplayName()
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for path[start] != name {
+				start++
+			}
+			cycle := append(append([]string{}, path[start:]...), name)
+			return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[i] = visiting
+		path = append(path, name)
+		for _, dep := range tools[i].DependsOn {
+			if err := visit(index[dep]); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		ordered = append(ordered, tools[i])
+		return nil
+	}
+
+	for i := range tools {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInstallOrder(t *testing.T) {
+	tools := []Tool{
+		{Type: "script", Alias: "lint", Source: Source{"task lint"}, DependsOn: []string{"task", "setup"}},
+		{Type: "go", Source: Source{"github.com/go-task/task/v3/cmd/task"}, Alias: "task"},
+		{Type: "script", Alias: "setup", Source: Source{"echo setup"}, DependsOn: []string{"task"}},
+		{Type: "npm", Source: Source{"prettier"}},
+	}
+
+	ordered, err := InstallOrder(tools)
+	if err != nil {
+		t.Fatalf("InstallOrder failed: %v", err)
+	}
+	var names []string
+	for _, tool := range ordered {
+		names = append(names, tool.DisplayName())
+	}
+	if want := []string{"task", "setup", "lint", "prettier"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected order %v, got %v", want, names)
+	}
+}
+
+func TestInstallOrderErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		tools []Tool
+		want  string
+	}{
+		{
+			"unknown",
+			[]Tool{{Type: "script", Alias: "a", DependsOn: []string{"jq"}}},
+			`tool "a" depends on unknown tool "jq"`,
+		},
+		{
+			"cycle",
+			[]Tool{
+				{Type: "script", Alias: "a", DependsOn: []string{"b"}},
+				{Type: "script", Alias: "b", DependsOn: []string{"c"}},
+				{Type: "script", Alias: "c", DependsOn: []string{"b"}},
+			},
+			"dependency cycle: b -> c -> b",
+		},
+		{
+			"self",
+			[]Tool{{Type: "script", Alias: "a", DependsOn: []string{"a"}}},
+			"dependency cycle: a -> a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := InstallOrder(tt.tools); err == nil || err.Error() != tt.want {
+				t.Errorf("expected error %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileNames are the configuration file names box looks for, in order of preference.
+var FileNames = []string{"box.yml", "box.yaml", ".box.yml"}
+
+// ProjectRootEnv overrides discovery with a fixed project root.
+const ProjectRootEnv = "BOX_PROJECT_ROOT"
+
+// Discover finds the configuration file for dir by searching dir and its
+// parents. If BOX_PROJECT_ROOT is set, only that directory is searched.
+// The project root is the directory containing the returned file.
+func Discover(dir string) (string, error) {
+	if root := os.Getenv(ProjectRootEnv); root != "" {
+		root, err := filepath.Abs(root)
+		if err != nil {
+			return "", err
+		}
+		if path := FindIn(root); path != "" {
+			return path, nil
+		}
+		return "", fmt.Errorf("no %s found in %s=%s", strings.Join(FileNames, ", "), ProjectRootEnv, root)
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if path := FindIn(dir); path != "" {
+			return path, nil
+		}
+
+		parentDir := filepath.Dir(dir)
+		if parentDir == dir {
+			break // Reached root directory
+		}
+		dir = parentDir
+	}
+
+	return "", fmt.Errorf("%s not found in current or parent directories", strings.Join(FileNames, ", "))
+}
+
+// FindIn returns the configuration file in dir, or an empty string if there is none.
+func FindIn(dir string) string {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
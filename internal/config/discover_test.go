@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "a", "b")
+	if err := os.MkdirAll(sub, 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ProjectRootEnv, "")
+
+	if _, err := Discover(sub); err == nil {
+		t.Error("expected error when no configuration exists")
+	}
+
+	writeFiles(t, dir, map[string]string{"box.yaml": "tools: []\n"})
+	path, err := Discover(sub)
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if path != filepath.Join(dir, "box.yaml") {
+		t.Errorf("expected %s, got %s", filepath.Join(dir, "box.yaml"), path)
+	}
+
+	// box.yml takes precedence and the nearest directory wins
+	writeFiles(t, dir, map[string]string{"box.yml": "tools: []\n", "a/.box.yml": "tools: []\n"})
+	if path, _ := Discover(sub); path != filepath.Join(dir, "a", ".box.yml") {
+		t.Errorf("expected nearest .box.yml, got %s", path)
+	}
+	if path, _ := Discover(dir); path != filepath.Join(dir, "box.yml") {
+		t.Errorf("expected box.yml to be preferred, got %s", path)
+	}
+
+	// BOX_PROJECT_ROOT disables walking up
+	t.Setenv(ProjectRootEnv, dir)
+	if path, _ := Discover(sub); path != filepath.Join(dir, "box.yml") {
+		t.Errorf("expected %s from %s, got %s", filepath.Join(dir, "box.yml"), ProjectRootEnv, path)
+	}
+	t.Setenv(ProjectRootEnv, sub)
+	if _, err := Discover(dir); err == nil {
+		t.Errorf("expected error when %s has no configuration", ProjectRootEnv)
+	}
+}
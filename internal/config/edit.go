@@ -0,0 +1,111 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AppendTools adds tools to the configuration file at path without discarding
+// comments or formatting of the existing entries. Tools whose display name is
+// already present are skipped. The file is created if it does not exist.
+// It returns the tools that were actually added.
+func AppendTools(path string, tools []Tool) ([]Tool, error) {
+	doc, err := loadDocument(path)
+	if err != nil {
+		return nil, err
+	}
+
+	root := doc.Content[0]
+	toolsNode := mappingValue(root, "tools")
+	if toolsNode == nil || toolsNode.Kind != yaml.SequenceNode {
+		if toolsNode != nil && !(toolsNode.Kind == yaml.ScalarNode && toolsNode.Tag == "!!null") {
+			return nil, fmt.Errorf("%s: line %d: tools must be a list", path, toolsNode.Line)
+		}
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if toolsNode != nil {
+			*toolsNode = *seq
+		} else {
+			root.Content = append(root.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "tools"},
+				seq,
+			)
+		}
+		toolsNode = mappingValue(root, "tools")
+	}
+
+	existing := make(map[string]bool)
+	for _, item := range toolsNode.Content {
+		var t Tool
+		if err := item.Decode(&t); err == nil {
+			existing[t.DisplayName()] = true
+		}
+	}
+
+	var added []Tool
+	for _, t := range tools {
+		if existing[t.DisplayName()] {
+			continue
+		}
+		node := &yaml.Node{}
+		if err := node.Encode(t); err != nil {
+			return nil, err
+		}
+		toolsNode.Content = append(toolsNode.Content, node)
+		existing[t.DisplayName()] = true
+		added = append(added, t)
+	}
+
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	return added, saveDocument(path, doc)
+}
+
+func loadDocument(path string) (*yaml.Node, error) {
+	doc := &yaml.Node{}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, doc); err != nil {
+			return nil, err
+		}
+	}
+
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		doc = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: expected a mapping at the top level", path)
+	}
+	return doc, nil
+}
+
+func saveDocument(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Clean(path), buf.Bytes(), 0600)
+}
+
+// mappingValue returns the value node for key in a YAML mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// load reads the file at path and merges it on top of the files it extends.
+// stack holds the files currently being loaded to detect cycles.
+func load(path string, stack []string) (*Config, error) {
+	cfg := &Config{}
+	if err := cfg.apply(path, stack); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyOverlay merges the overlay file at path on top of c. Unlike extended
+// files, an overlay can remove tools defined by c.
+func (c *Config) applyOverlay(path string) error {
+	if err := c.apply(path, nil); err != nil {
+		return err
+	}
+	c.Overlays = append(c.Overlays, path)
+	return nil
+}
+
+// apply merges the files extended by the file at path into c, drops the tools
+// it removes and finally merges the file itself.
+func (c *Config) apply(path string, stack []string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	for i, p := range stack {
+		if p == abs {
+			chain := append(append([]string{}, stack[i:]...), abs)
+			return fmt.Errorf("extends cycle: %s", strings.Join(chain, " -> "))
+		}
+	}
+	stack = append(stack, abs)
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	var own Config
+	if err := yaml.Unmarshal(data, &own); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	for _, pattern := range own.Extends {
+		files, err := resolveExtends(filepath.Dir(path), pattern)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for _, file := range files {
+			base, err := load(file, stack)
+			if err != nil {
+				return err
+			}
+			// A workspace belongs to the file that declares it
+			base.Workspace = nil
+			c.merge(base)
+		}
+	}
+
+	for _, name := range own.Remove {
+		c.removeTool(name)
+	}
+
+	for i := range own.Tools {
+		own.Tools[i].Origin = path
+	}
+	own.EnvOrigin = make(map[string]string, len(own.Env))
+	for k := range own.Env {
+		own.EnvOrigin[k] = path
+	}
+	own.Files = []string{path}
+	c.merge(&own)
+	return nil
+}
+
+// resolveExtends returns the files matched by an extends entry. Relative
+// patterns are resolved against dir. Globs may match nothing, plain paths must exist.
+func resolveExtends(dir, pattern string) ([]string, error) {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(dir, pattern)
+	}
+	if !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}, nil
+	}
+	// Glob returns matches in lexical order, which keeps merging deterministic
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid extends pattern %q: %w", pattern, err)
+	}
+	return files, nil
+}
+
+// merge applies other on top of c. Tools are keyed by display name: a tool
+// already present is replaced in place, new tools are appended. Env variables
+// and registries are merged with other taking precedence.
+func (c *Config) merge(other *Config) {
+	for _, t := range other.Tools {
+		replaced := false
+		for i := range c.Tools {
+			if c.Tools[i].DisplayName() == t.DisplayName() {
+				c.Tools[i] = t
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			c.Tools = append(c.Tools, t)
+		}
+	}
+
+	if len(other.Env) > 0 && c.Env == nil {
+		c.Env = make(map[string]string, len(other.Env))
+	}
+	if len(other.EnvOrigin) > 0 && c.EnvOrigin == nil {
+		c.EnvOrigin = make(map[string]string, len(other.EnvOrigin))
+	}
+	for k, v := range other.Env {
+		c.Env[k] = v
+	}
+	for k, v := range other.EnvOrigin {
+		c.EnvOrigin[k] = v
+	}
+
+	c.GoModTools = c.GoModTools || other.GoModTools
+	c.SharedStore = c.SharedStore || other.SharedStore
+	c.Shims = c.Shims || other.Shims
+	if len(other.Registries) > 0 && c.Registries == nil {
+		c.Registries = make(map[string]Registry, len(other.Registries))
+	}
+	for k, v := range other.Registries {
+		c.Registries[k] = v
+	}
+	if other.Workspace != nil {
+		c.Workspace = other.Workspace
+	}
+	c.Files = append(c.Files, other.Files...)
+}
+
+// removeTool drops the tool with the given display name.
+func (c *Config) removeTool(name string) {
+	tools := c.Tools[:0]
+	for _, t := range c.Tools {
+		if t.DisplayName() != name {
+			tools = append(tools, t)
+		}
+	}
+	c.Tools = tools
+}
+
+// MarshalResolved renders the merged configuration as YAML with each tool and
+// env variable annotated by the file it was defined in. Origins are shown
+// relative to dir.
+func (c *Config) MarshalResolved(dir string) ([]byte, error) {
+	resolved := *c
+	resolved.Extends = nil
+	resolved.Remove = nil
+
+	var root yaml.Node
+	if err := root.Encode(resolved); err != nil {
+		return nil, err
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	origin := func(path string) string {
+		if abs, err := filepath.Abs(path); err == nil {
+			if rel, err := filepath.Rel(dir, abs); err == nil {
+				return "from " + rel
+			}
+		}
+		return "from " + path
+	}
+
+	if tools := mappingValue(&root, "tools"); tools != nil {
+		for i, item := range tools.Content {
+			if c.Tools[i].Origin != "" {
+				item.HeadComment = origin(c.Tools[i].Origin)
+			}
+		}
+	}
+	if env := mappingValue(&root, "env"); env != nil {
+		for i := 0; i+1 < len(env.Content); i += 2 {
+			if file, ok := c.EnvOrigin[env.Content[i].Value]; ok {
+				env.Content[i+1].LineComment = origin(file)
+			}
+		}
+	}
+
+	var buf strings.Builder
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&root); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return []byte(buf.String()), nil
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadExtends(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"shared/box.base.yml": `
+tools:
+  - type: npm
+    source: prettier
+  - type: go
+    source: example.com/lint
+    version: v1.0.0
+env:
+  A: base
+  B: base
+`,
+		"app/tools/b.yml": "tools:\n  - type: uv\n    source: ruff\n",
+		"app/tools/a.yml": "tools:\n  - type: cargo\n    source: jj-cli\n",
+		"app/box.yml": `
+extends: [../shared/box.base.yml, ./tools/*.yml]
+remove: [prettier]
+tools:
+  - type: go
+    source: example.com/lint
+    version: v2.0.0
+env:
+  A: app
+`,
+	})
+
+	path := filepath.Join(dir, "app", "box.yml")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	var names []string
+	for _, tool := range cfg.Tools {
+		names = append(names, tool.DisplayName())
+	}
+	// Overrides keep the position of the inherited tool, globs are merged in lexical order
+	if got := strings.Join(names, ","); got != "example.com/lint,jj-cli,ruff" {
+		t.Errorf("unexpected tools: %s", got)
+	}
+	if cfg.Tools[0].Version != "v2.0.0" || cfg.Tools[0].Origin != path {
+		t.Errorf("expected override from %s, got %+v", path, cfg.Tools[0])
+	}
+	if cfg.Env["A"] != "app" || cfg.Env["B"] != "base" {
+		t.Errorf("unexpected env: %v", cfg.Env)
+	}
+	if cfg.EnvOrigin["B"] != filepath.Join(dir, "shared", "box.base.yml") {
+		t.Errorf("unexpected origin for B: %s", cfg.EnvOrigin["B"])
+	}
+	if len(cfg.Files) != 4 || cfg.Files[3] != path {
+		t.Errorf("unexpected files: %v", cfg.Files)
+	}
+
+	data, err := cfg.MarshalResolved(filepath.Join(dir, "app"))
+	if err != nil {
+		t.Fatalf("MarshalResolved failed: %v", err)
+	}
+	for _, want := range []string{"# from box.yml\n  - type: go", "# from tools/a.yml", "B: base # from ../shared/box.base.yml"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected resolved config to contain %q:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "extends") {
+		t.Errorf("resolved config must not contain extends:\n%s", data)
+	}
+}
+
+func TestLoadExtendsCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"box.yml": "extends: [a.yml]\n",
+		"a.yml":   "extends: [b.yml]\n",
+		"b.yml":   "extends: [a.yml]\n",
+	})
+
+	_, err := Load(filepath.Join(dir, "box.yml"))
+	if err == nil || !strings.Contains(err.Error(), "extends cycle") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+}
+
+func TestLoadExtendsWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"app/box.yml":     "extends: [../shared/base.yml]\n",
+		"shared/base.yml": "workspace:\n  members: [services/*]\nenv:\n  A: b\n",
+	})
+
+	cfg, err := Load(filepath.Join(dir, "app", "box.yml"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Workspace != nil {
+		t.Errorf("expected the workspace of an extended file not to be inherited, got %+v", cfg.Workspace)
+	}
+	if cfg.Env["A"] != "b" {
+		t.Errorf("expected the env of the extended file, got %v", cfg.Env)
+	}
+}
+
+func TestLoadExtendsMissing(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"box.yml": "extends: [missing.yml, none/*.yml]\n"})
+
+	if _, err := Load(filepath.Join(dir, "box.yml")); err == nil {
+		t.Error("expected error for missing extended file")
+	}
+}
+
+func TestCacheKeyExtends(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"box.yml":  "extends: [base.yml]\n",
+		"base.yml": "tools: []\n",
+	})
+
+	key, err := CacheKey(filepath.Join(dir, "box.yml"))
+	if err != nil {
+		t.Fatalf("CacheKey failed: %v", err)
+	}
+
+	writeFiles(t, dir, map[string]string{"base.yml": "tools:\n  - type: uv\n    source: ruff\n"})
+	changed, err := CacheKey(filepath.Join(dir, "box.yml"))
+	if err != nil {
+		t.Fatalf("CacheKey failed: %v", err)
+	}
+	if changed == key {
+		t.Error("expected key to change when an extended file changes")
+	}
+}
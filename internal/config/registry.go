@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Registry configures the package registry mirror used by an installer type.
+type Registry struct {
+	URL      string `yaml:"url"`                 // Mirror or proxy URL
+	TokenEnv string `yaml:"token_env,omitempty"` // Environment variable holding the auth token
+	Username string `yaml:"username,omitempty"`  // Optional user name sent with the token
+}
+
+// Token returns the auth token of the registry from the environment. It is an
+// error if token_env is set but the variable is empty.
+func (r Registry) Token() (string, error) {
+	if r.TokenEnv == "" {
+		return "", nil
+	}
+	token := os.Getenv(r.TokenEnv)
+	if token == "" {
+		return "", fmt.Errorf("registry token variable %s is not set", r.TokenEnv)
+	}
+	return token, nil
+}
+
+// UserConfig holds settings of the current user that apply to all projects.
+type UserConfig struct {
+	Registries map[string]Registry `yaml:"registries,omitempty"`
+}
+
+// UserConfigPath returns the path of the user configuration,
+// $XDG_CONFIG_HOME/box/config.yml on Linux.
+func UserConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine config directory: %w", err)
+	}
+	return filepath.Join(dir, "box", "config.yml"), nil
+}
+
+// LoadUserConfig loads the user configuration. A missing file results in an
+// empty configuration.
+func LoadUserConfig() (*UserConfig, error) {
+	path, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &UserConfig{}, nil
+		}
+		return nil, err
+	}
+
+	var cfg UserConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// ResolveRegistries returns the registries of the user configuration with the
+// ones of the project taking precedence for the same installer type.
+func (c *Config) ResolveRegistries(user *UserConfig) map[string]Registry {
+	registries := make(map[string]Registry)
+	if user != nil {
+		for k, v := range user.Registries {
+			registries[k] = v
+		}
+	}
+	for k, v := range c.Registries {
+		registries[k] = v
+	}
+	return registries
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveRegistries(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	userPath, err := UserConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(userPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	user := `registries:
+  go:
+    url: https://goproxy.corp.example
+  npm:
+    url: https://npm.corp.example
+    token_env: NPM_TOKEN
+`
+	if err := os.WriteFile(userPath, []byte(user), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"base.yml": "registries:\n  uv:\n    url: https://pypi.base.example/simple\n",
+		"box.yml":  "extends: [base.yml]\nregistries:\n  npm:\n    url: https://npm.project.example\n",
+	})
+	cfg, err := Load(filepath.Join(dir, "box.yml"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	userCfg, err := LoadUserConfig()
+	if err != nil {
+		t.Fatalf("LoadUserConfig failed: %v", err)
+	}
+	registries := cfg.ResolveRegistries(userCfg)
+
+	expected := map[string]string{
+		"go":  "https://goproxy.corp.example",
+		"npm": "https://npm.project.example",
+		"uv":  "https://pypi.base.example/simple",
+	}
+	if len(registries) != len(expected) {
+		t.Fatalf("expected %d registries, got %v", len(expected), registries)
+	}
+	for toolType, url := range expected {
+		if registries[toolType].URL != url {
+			t.Errorf("expected %s registry %s, got %+v", toolType, url, registries[toolType])
+		}
+	}
+	if registries["npm"].TokenEnv != "" {
+		t.Errorf("expected the project registry to replace the user one entirely, got %+v", registries["npm"])
+	}
+}
+
+func TestRegistryToken(t *testing.T) {
+	t.Setenv("BOX_TEST_TOKEN", "")
+	reg := Registry{URL: "https://example.com", TokenEnv: "BOX_TEST_TOKEN"}
+	if _, err := reg.Token(); err == nil {
+		t.Error("expected an error for an unset token variable")
+	}
+
+	t.Setenv("BOX_TEST_TOKEN", "secret")
+	if token, err := reg.Token(); err != nil || token != "secret" {
+		t.Errorf("expected token secret, got %q (%v)", token, err)
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// SchemaID is the identifier of the generated JSON Schema.
+const SchemaID = "https://github.com/sebakri/box/box.schema.json"
+
+// descriptions documents the fields of the configuration in the JSON Schema,
+// keyed by "<type>.<yaml key>".
+var descriptions = map[string]string{
+	"Config.tools":           "Tools to install into the project-local .box directory.",
+	"Config.env":             "Environment variables exported by 'box env' and 'box run'.",
+	"Config.go_mod_tools":    "Sync go tools from the tool directives in go.mod.",
+	"Config.extends":         "Files or globs, relative to this file, merged before it. Later definitions override earlier ones.",
+	"Config.remove":          "Display names of inherited tools to drop.",
+	"Config.workspace":       "Declares this project as the root of a workspace whose members share its .box directory.",
+	"Config.shared_store":    "Install tools into the shared store in the user cache directory and link them into .box/bin.",
+	"Config.shims":           "Link the binaries of all tools into .box/bin as shims that run them with 'box run', so they get the box environment and sandbox when run directly.",
+	"Config.registries":      "Package registry mirrors keyed by tool type. Override the user configuration in $XDG_CONFIG_HOME/box/config.yml.",
+	"Registry.url":           "Mirror or proxy URL of the registry.",
+	"Registry.token_env":     "Environment variable holding the auth token. The token is never written to disk or logged.",
+	"Registry.username":      "Optional user name sent with the token.",
+	"Workspace.members":      "Globs of member directories, relative to the workspace root.",
+	"Tool.type":              "Installer used for the tool.",
+	"Tool.source":            "Package path, package name or script commands.",
+	"Tool.alias":             "Optional name used for display.",
+	"Tool.version":           "Optional version. Go tools require a 'v' prefix (e.g. v1.2.3).",
+	"Tool.binaries":          "Binaries provided by the tool. Detected from the source if omitted.",
+	"Tool.args":              "Additional arguments passed to the installer.",
+	"Tool.depends_on":        "Display names of tools that are installed before this one, e.g. tools used by an install script.",
+	"Tool.post_install":      "Shell commands run in the project after the tool is installed, in the same environment and sandbox as script tools.",
+	"Tool.verify":            "Command that checks the installed tool. The install fails if it exits non-zero or its output does not match expect.",
+	"Tool.version_command":   "Shell command printing the installed version, e.g. 'task --version'. Overrides how the installer detects the version.",
+	"Tool.bin_name_template": "Go template for the names of the binaries in .box/bin, with {{.Name}} and {{.Version}}, e.g. '{{.Name}}@{{.Version}}'. Allows several versions of a tool side by side.",
+	"Tool.rename":            "Names under which binaries are linked into .box/bin, keyed by the binary name, e.g. {golangci-lint: lint}. Takes precedence over bin_name_template.",
+	"Tool.shim":              "Link the binaries into .box/bin as shims that run them with 'box run', so they get the box environment and sandbox when run directly.",
+	"Verify.command":         "Shell command, e.g. 'golangci-lint version'.",
+	"Verify.expect":          "Regular expression the combined output of the command must match.",
+}
+
+// Schema returns a JSON Schema describing box.yml. supportedTypes lists the
+// allowed values of a tool's type.
+func Schema(supportedTypes []string) ([]byte, error) {
+	defs := map[string]any{}
+	root := structSchema(reflect.TypeOf(Config{}), defs)
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = SchemaID
+	root["title"] = "box.yml"
+	root["$defs"] = defs
+
+	tool := defs["Tool"].(map[string]any)
+	tool["properties"].(map[string]any)["type"].(map[string]any)["enum"] = supportedTypes
+	tool["required"] = []string{"type", "source"}
+	defs["Registry"].(map[string]any)["required"] = []string{"url"}
+	defs["Verify"].(map[string]any)["required"] = []string{"command"}
+	root["properties"].(map[string]any)["registries"].(map[string]any)["propertyNames"] = map[string]any{"enum": supportedTypes}
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+var sourceType = reflect.TypeOf(Source{})
+
+// schemaFor builds the schema of t from its yaml tags. Named struct types are
+// added to defs and referenced.
+func schemaFor(t reflect.Type, defs map[string]any) map[string]any {
+	switch {
+	case t == sourceType:
+		return map[string]any{"oneOf": []any{
+			map[string]any{"type": "string"},
+			map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		}}
+	case t.Kind() == reflect.Struct:
+		if _, ok := defs[t.Name()]; !ok {
+			// Reserve the name first so recursive types terminate
+			defs[t.Name()] = map[string]any{}
+			defs[t.Name()] = structSchema(t, defs)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	case t.Kind() == reflect.Pointer:
+		return schemaFor(t.Elem(), defs)
+	case t.Kind() == reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem(), defs)}
+	case t.Kind() == reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem(), defs)}
+	case t.Kind() == reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case t.Kind() == reflect.Int:
+		return map[string]any{"type": "integer"}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+func structSchema(t reflect.Type, defs map[string]any) map[string]any {
+	props := map[string]any{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		prop := schemaFor(field.Type, defs)
+		if desc, ok := descriptions[t.Name()+"."+name]; ok {
+			prop["description"] = desc
+		}
+		props[name] = prop
+	}
+	return map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+}
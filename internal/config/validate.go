@@ -0,0 +1,452 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Issue is a problem found while validating a configuration file.
+type Issue struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+}
+
+var (
+	// lineMessage matches the "line N: message" format of yaml.v3 errors.
+	lineMessage = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	// unknownField matches the error reported for unknown keys in strict mode.
+	unknownField = regexp.MustCompile(`^field (\S+) not found in type`)
+)
+
+// Validate strictly decodes the configuration file at path, the files it
+// extends and its overlays, and checks them for semantic problems.
+// supportedTypes lists the tool types that can be installed. The issues of
+// path come first, followed by those of the other files in merge order, each
+// sorted by position. An error is only returned if the file at path cannot
+// be read.
+func Validate(path string, supportedTypes []string) ([]Issue, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	root := &validator{file: path}
+	cfg, doc := root.decode(data)
+	if doc == nil {
+		return root.issues, nil
+	}
+	if len(doc.Content) == 0 {
+		root.add(1, 1, "configuration is empty")
+		return root.issues, nil
+	}
+	if cfg == nil {
+		return root.issues, nil
+	}
+
+	// Dependencies can name tools from extended files and overlays
+	merged, err := Load(path)
+	if err != nil {
+		// Check the files that still load: the committed part and the overlays
+		merged, err = LoadCommitted(path)
+		if err != nil {
+			if extends := mappingValue(doc.Content[0], "extends"); extends != nil && len(cfg.Extends) > 0 {
+				root.addNode(extends, "failed to resolve extends: %v", err)
+			}
+			merged = &Config{Tools: slices.Clone(cfg.Tools), Files: []string{path}}
+			for i := range merged.Tools {
+				merged.Tools[i].Origin = path
+			}
+		}
+		merged.Files = append(merged.Files, filepath.Join(filepath.Dir(path), LocalFile))
+		merged.Files = append(merged.Files, filepath.SplitList(os.Getenv(OverlayEnv))...)
+	}
+
+	validators := []*validator{root}
+	root.check(cfg, doc, merged, supportedTypes)
+	for _, file := range mergedFiles(path, merged.Files) {
+		data, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			continue
+		}
+		v := &validator{file: file}
+		if cfg, doc := v.decode(data); cfg != nil && len(doc.Content) > 0 {
+			v.check(cfg, doc, merged, supportedTypes)
+		}
+		validators = append(validators, v)
+	}
+
+	checkBinaries(validators, merged)
+	if !slices.ContainsFunc(validators, func(v *validator) bool { return v.invalidDeps }) {
+		if _, err := InstallOrder(merged.Tools); err != nil {
+			at := doc.Content[0]
+			if tools := mappingValue(at, "tools"); tools != nil {
+				at = tools
+			}
+			root.addNode(at, "%v", err)
+		}
+	}
+
+	var issues []Issue
+	for _, v := range validators {
+		sort.SliceStable(v.issues, func(i, j int) bool {
+			if v.issues[i].Line != v.issues[j].Line {
+				return v.issues[i].Line < v.issues[j].Line
+			}
+			return v.issues[i].Column < v.issues[j].Column
+		})
+		issues = append(issues, v.issues...)
+	}
+	return issues, nil
+}
+
+// mergedFiles returns the existing files other than path, without
+// duplicates, in the order they are listed.
+func mergedFiles(path string, files []string) []string {
+	seen := map[string]bool{filepath.Clean(path): true}
+	var existing []string
+	for _, file := range files {
+		if file == "" || seen[filepath.Clean(file)] {
+			continue
+		}
+		seen[filepath.Clean(file)] = true
+		if _, err := os.Stat(file); err == nil {
+			existing = append(existing, file)
+		}
+	}
+	return existing
+}
+
+type validator struct {
+	file   string
+	issues []Issue
+
+	cfg         *Config    // Strictly decoded content of file
+	doc         *yaml.Node // Document node of file
+	invalidDeps bool       // Whether unknown or self dependencies were reported
+}
+
+// decode parses data strictly. It returns a nil document if data is not
+// valid YAML and a nil configuration if it cannot be decoded at all. Type
+// errors are reported and the rest of the configuration is still returned.
+func (v *validator) decode(data []byte) (*Config, *yaml.Node) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		v.addError(nil, err)
+		return nil, nil
+	}
+	if len(doc.Content) == 0 {
+		return &Config{}, &doc
+	}
+
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil {
+		v.addError(doc.Content[0], err)
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			// Without a decoded configuration there is nothing left to check
+			return nil, &doc
+		}
+	}
+	v.cfg, v.doc = &cfg, &doc
+	return &cfg, &doc
+}
+
+// check runs the checks that apply to a single file. merged is the
+// configuration with extended files and overlays.
+func (v *validator) check(cfg *Config, doc *yaml.Node, merged *Config, supportedTypes []string) {
+	tools := mappingValue(doc.Content[0], "tools")
+	v.checkTools(cfg, tools, supportedTypes)
+	v.checkDependencies(cfg, merged, tools)
+	v.checkRegistries(cfg, mappingValue(doc.Content[0], "registries"), supportedTypes)
+}
+
+// checkBinaries reports binaries that more than one tool of the merged
+// configuration provides, at the tool that is merged last.
+func checkBinaries(validators []*validator, merged *Config) {
+	byFile := make(map[string]*validator, len(validators))
+	for _, v := range validators {
+		byFile[filepath.Clean(v.file)] = v
+	}
+
+	owners := make(map[string]Tool)
+	for _, t := range merged.Tools {
+		name := t.DisplayName()
+		for _, bin := range t.LinkNames() {
+			owner, ok := owners[bin]
+			if !ok {
+				owners[bin] = t
+				continue
+			}
+			v := byFile[filepath.Clean(t.Origin)]
+			if v == nil || v.cfg == nil {
+				continue
+			}
+			node := v.toolNode(name)
+			if node == nil {
+				continue
+			}
+			if n := mappingValue(node, "binaries"); n != nil {
+				node = n
+			} else if n := mappingValue(node, "source"); n != nil {
+				node = n
+			}
+			if filepath.Clean(owner.Origin) == filepath.Clean(t.Origin) {
+				v.addNode(node, "binary %q is also provided by %q", bin, owner.DisplayName())
+			} else {
+				v.addNode(node, "binary %q is also provided by %q in %s", bin, owner.DisplayName(), owner.Origin)
+			}
+		}
+	}
+}
+
+// toolNode returns the node of the last tool in the file with the given
+// display name, which is the one that is merged.
+func (v *validator) toolNode(name string) *yaml.Node {
+	tools := mappingValue(v.doc.Content[0], "tools")
+	if tools == nil || tools.Kind != yaml.SequenceNode || len(tools.Content) != len(v.cfg.Tools) {
+		return nil
+	}
+	for i := len(v.cfg.Tools) - 1; i >= 0; i-- {
+		if v.cfg.Tools[i].DisplayName() == name {
+			return tools.Content[i]
+		}
+	}
+	return nil
+}
+
+func (v *validator) add(line, column int, format string, args ...any) {
+	v.issues = append(v.issues, Issue{File: v.file, Line: line, Column: column, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) addNode(node *yaml.Node, format string, args ...any) {
+	v.add(node.Line, node.Column, format, args...)
+}
+
+// addError converts a yaml.v3 error into issues. yaml.v3 only reports lines,
+// so the column is taken from the matching node in root.
+func (v *validator) addError(root *yaml.Node, err error) {
+	messages := []string{err.Error()}
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	}
+
+	for _, msg := range messages {
+		match := lineMessage.FindStringSubmatch(msg)
+		if match == nil {
+			v.add(1, 1, "%s", strings.TrimPrefix(msg, "yaml: "))
+			continue
+		}
+		line, _ := strconv.Atoi(match[1])
+		text := match[2]
+
+		column := 1
+		key := ""
+		if m := unknownField.FindStringSubmatch(text); m != nil {
+			key = m[1]
+			text = fmt.Sprintf("unknown field %q", key)
+		}
+		if node := findNode(root, line, key); node != nil {
+			column = node.Column
+		}
+		v.add(line, column, "%s", text)
+	}
+}
+
+// findNode returns the first node on the given line. If key is set, only
+// mapping keys with that value are considered.
+func findNode(node *yaml.Node, line int, key string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k := node.Content[i]
+			if k.Line == line && (key == "" || k.Value == key) {
+				return k
+			}
+			if found := findNode(node.Content[i+1], line, key); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	if node.Line == line && key == "" {
+		return node
+	}
+	for _, child := range node.Content {
+		if found := findNode(child, line, key); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func (v *validator) checkTools(cfg *Config, toolsNode *yaml.Node, supportedTypes []string) {
+	if toolsNode == nil || toolsNode.Kind != yaml.SequenceNode || len(toolsNode.Content) != len(cfg.Tools) {
+		return
+	}
+
+	supported := make(map[string]bool, len(supportedTypes))
+	for _, t := range supportedTypes {
+		supported[t] = true
+	}
+
+	names := make(map[string]int)
+	for i, t := range cfg.Tools {
+		node := toolsNode.Content[i]
+		at := func(key string) *yaml.Node {
+			if n := mappingValue(node, key); n != nil {
+				return n
+			}
+			return node
+		}
+
+		if t.Type == "" {
+			v.addNode(node, "tool is missing a type")
+		} else if !supported[t.Type] {
+			v.addNode(at("type"), "unknown tool type %q (supported: %s)", t.Type, strings.Join(supportedTypes, ", "))
+		}
+
+		if strings.TrimSpace(t.Source.String()) == "" {
+			v.addNode(at("source"), "tool source must not be empty")
+			continue
+		}
+
+		if t.Type == "go" && t.Version != "" && t.Version[0] >= '0' && t.Version[0] <= '9' {
+			v.addNode(at("version"), "go tools require a 'v' prefix for versions (e.g., v%s instead of %s)", t.Version, t.Version)
+		}
+
+		for _, command := range t.PostInstall {
+			if strings.TrimSpace(command) == "" {
+				v.addNode(at("post_install"), "post_install commands must not be empty")
+				break
+			}
+		}
+		if t.Verify != nil {
+			verify := at("verify")
+			if strings.TrimSpace(t.Verify.Command) == "" {
+				v.addNode(verify, "verify is missing a command")
+			}
+			if _, err := regexp.Compile(t.Verify.Expect); err != nil {
+				if n := mappingValue(verify, "expect"); n != nil {
+					verify = n
+				}
+				v.addNode(verify, "invalid verify expect pattern: %v", err)
+			}
+		}
+
+		name := t.DisplayName()
+		if first, ok := names[name]; ok {
+			v.addNode(at("source"), "duplicate tool %q (first defined on line %d)", name, first)
+		} else {
+			names[name] = node.Line
+		}
+
+		if t.BinNameTemplate != "" {
+			for _, b := range t.binaryNames() {
+				if _, err := t.LinkName(b); err != nil {
+					v.addNode(at("bin_name_template"), "%v", err)
+					break
+				}
+			}
+		}
+
+		if len(t.Rename) > 0 {
+			v.checkRename(t, at("rename"))
+		}
+	}
+}
+
+// checkRename reports renames of binaries the tool does not provide and
+// invalid new names.
+func (v *validator) checkRename(t Tool, node *yaml.Node) {
+	keys := make([]string, 0, len(t.Rename))
+	for k := range t.Rename {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// The binaries of scripts are only known if they are listed
+	known := t.Type != "script" || len(t.Binaries) > 0
+	for _, k := range keys {
+		at := node
+		if n := mappingValue(node, k); n != nil {
+			at = n
+		}
+		if known && !slices.Contains(t.binaryNames(), k) {
+			v.addNode(at, "rename of unknown binary %q (binaries: %s)", k, strings.Join(t.binaryNames(), ", "))
+			continue
+		}
+		if _, err := t.LinkName(k); err != nil {
+			v.addNode(at, "%v", err)
+		}
+	}
+}
+
+// checkDependencies checks the depends_on entries of the tools in cfg against
+// the tools of merged, the configuration with extended files and overlays.
+func (v *validator) checkDependencies(cfg, merged *Config, toolsNode *yaml.Node) {
+	if toolsNode == nil || toolsNode.Kind != yaml.SequenceNode || len(toolsNode.Content) != len(cfg.Tools) {
+		return
+	}
+
+	names := make(map[string]bool, len(cfg.Tools)+len(merged.Tools))
+	for _, t := range append(slices.Clone(cfg.Tools), merged.Tools...) {
+		names[t.DisplayName()] = true
+	}
+
+	valid := true
+	for i, t := range cfg.Tools {
+		deps := mappingValue(toolsNode.Content[i], "depends_on")
+		for j, dep := range t.DependsOn {
+			node := deps
+			if deps != nil && deps.Kind == yaml.SequenceNode && j < len(deps.Content) {
+				node = deps.Content[j]
+			}
+			switch {
+			case dep == t.DisplayName():
+				v.addNode(node, "tool %q depends on itself", dep)
+				valid = false
+			case !names[dep]:
+				v.addNode(node, "tool %q depends on unknown tool %q", t.DisplayName(), dep)
+				valid = false
+			}
+		}
+	}
+	v.invalidDeps = v.invalidDeps || !valid
+}
+
+func (v *validator) checkRegistries(cfg *Config, node *yaml.Node, supportedTypes []string) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !slices.Contains(supportedTypes, key.Value) {
+			v.addNode(key, "registry for unknown tool type %q (supported: %s)", key.Value, strings.Join(supportedTypes, ", "))
+			continue
+		}
+		if strings.TrimSpace(cfg.Registries[key.Value].URL) == "" {
+			v.addNode(value, "registry for %q is missing a url", key.Value)
+		}
+	}
+}
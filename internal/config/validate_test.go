@@ -0,0 +1,269 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testTypes = []string{"cargo", "gem", "go", "npm", "script", "uv"}
+
+func TestValidate(t *testing.T) {
+	content := `tools:
+  - type: go
+    source: example.com/tool
+    version: 1.2.3
+  - type: npm
+    source: cowsay
+    binarys: [cowsay]
+  - type: brew
+    source: jq
+  - type: uv
+    source: ""
+  - type: go
+    source: example.com/tool
+  - type: cargo
+    source: other
+    binaries: [tool]
+env:
+  KEY: value
+registries:
+  brew:
+    url: https://brew.example
+  npm:
+    token_env: NPM_TOKEN
+`
+	path := filepath.Join(t.TempDir(), "box.yml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := Validate(path, testTypes)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	expected := []string{
+		path + `:4:14: go tools require a 'v' prefix`,
+		path + `:7:5: unknown field "binarys"`,
+		path + `:8:11: unknown tool type "brew"`,
+		path + `:11:13: tool source must not be empty`,
+		path + `:13:13: duplicate tool "example.com/tool" (first defined on line 2)`,
+		path + `:16:15: binary "tool" is also provided by "example.com/tool"`,
+		path + `:20:3: registry for unknown tool type "brew"`,
+		path + `:23:5: registry for "npm" is missing a url`,
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues, got %d: %v", len(expected), len(issues), issues)
+	}
+	for i, want := range expected {
+		if !strings.HasPrefix(issues[i].String(), want) {
+			t.Errorf("Issue %d: expected prefix %q, got %q", i, want, issues[i].String())
+		}
+	}
+}
+
+func TestValidateValid(t *testing.T) {
+	content := `tools:
+  - type: go
+    source: github.com/go-task/task/v3/cmd/task
+    version: v3.40.0
+  - type: script
+    alias: hello
+    source:
+      - echo hello
+  - type: script
+    alias: world
+    source: echo world
+    depends_on: [hello, github.com/go-task/task/v3/cmd/task]
+go_mod_tools: true
+`
+	path := filepath.Join(t.TempDir(), "box.yml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := Validate(path, testTypes)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Expected no issues, got %v", issues)
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown", "tools:\n  - type: script\n    alias: a\n    source: echo a\n    depends_on: [jq]\n", `:5:18: tool "a" depends on unknown tool "jq"`},
+		{"self", "tools:\n  - type: script\n    alias: a\n    source: echo a\n    depends_on:\n      - a\n", `:6:9: tool "a" depends on itself`},
+		{"cycle", "tools:\n  - type: script\n    alias: a\n    source: echo a\n    depends_on: [b]\n  - type: script\n    alias: b\n    source: echo b\n    depends_on: [a]\n", `:2:3: dependency cycle: a -> b -> a`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "box.yml")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			issues, err := Validate(path, testTypes)
+			if err != nil {
+				t.Fatalf("Validate failed: %v", err)
+			}
+			if len(issues) != 1 || !strings.HasPrefix(issues[0].String(), path+tt.want) {
+				t.Errorf("Expected issue %q, got %v", tt.want, issues)
+			}
+		})
+	}
+}
+
+func TestValidateDependenciesMerged(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"base.yml": "tools:\n  - type: go\n    source: github.com/go-task/task/v3/cmd/task\n    alias: task\n",
+		LocalFile:  "tools:\n  - type: script\n    alias: local\n    source: echo local\n",
+		"box.yml":  "extends: [base.yml]\ntools:\n  - type: script\n    alias: generate\n    source: task generate\n    depends_on: [task, local]\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	issues, err := Validate(filepath.Join(dir, "box.yml"), testTypes)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected dependencies on extended and local tools to be valid, got %v", issues)
+	}
+}
+
+func TestValidateMergedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"base.yml": "tools:\n  - type: go\n    source: example.com/tool\n    versoin: v1.0.0\n  - type: go\n    source: example.com/tool\n  - type: npm\n    source: cowsay\n    binaries: [task]\n",
+		LocalFile:  "env: [oops]\n",
+		"box.yml":  "extends: [base.yml]\ntools:\n  - type: go\n    source: example.com/tool\n    version: v1.1.0\n  - type: go\n    source: github.com/go-task/task/v3/cmd/task\n",
+	})
+	path := filepath.Join(dir, "box.yml")
+	base := filepath.Join(dir, "base.yml")
+	local := filepath.Join(dir, LocalFile)
+
+	issues, err := Validate(path, testTypes)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	// Overriding a tool of an extended file is not a duplicate
+	expected := []string{
+		path + `:7:13: binary "task" is also provided by "cowsay" in ` + base,
+		base + `:4:5: unknown field "versoin"`,
+		base + `:6:13: duplicate tool "example.com/tool" (first defined on line 2)`,
+		local + `:1:1: cannot unmarshal !!seq into map[string]string`,
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues, got %d: %v", len(expected), len(issues), issues)
+	}
+	for i, want := range expected {
+		if !strings.HasPrefix(issues[i].String(), want) {
+			t.Errorf("Issue %d: expected prefix %q, got %q", i, want, issues[i].String())
+		}
+	}
+}
+
+func TestValidateHooks(t *testing.T) {
+	content := `tools:
+  - type: go
+    source: github.com/golangci/golangci-lint/v2/cmd/golangci-lint
+    post_install:
+      - ""
+    verify:
+      command: golangci-lint version
+      expect: "2.(9"
+  - type: npm
+    source: prettier
+    verify:
+      expect: "3"
+`
+	path := filepath.Join(t.TempDir(), "box.yml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := Validate(path, testTypes)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	expected := []string{
+		path + `:5:7: post_install commands must not be empty`,
+		path + `:8:15: invalid verify expect pattern`,
+		path + `:12:7: verify is missing a command`,
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues, got %d: %v", len(expected), len(issues), issues)
+	}
+	for i, want := range expected {
+		if !strings.HasPrefix(issues[i].String(), want) {
+			t.Errorf("Issue %d: expected prefix %q, got %q", i, want, issues[i].String())
+		}
+	}
+}
+
+func TestValidateSyntaxError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "box.yml")
+	if err := os.WriteFile(path, []byte("tools:\n  - type: go\n source: x\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := Validate(path, testTypes)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Line == 0 {
+		t.Errorf("Expected one issue with a line number, got %v", issues)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	data, err := Schema(testTypes)
+	if err != nil {
+		t.Fatalf("Schema failed: %v", err)
+	}
+
+	var schema struct {
+		Properties map[string]any `json:"properties"`
+		Defs       map[string]struct {
+			Properties map[string]struct {
+				Enum []string `json:"enum"`
+			} `json:"properties"`
+			AdditionalProperties bool `json:"additionalProperties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+
+	for _, key := range []string{"tools", "env", "go_mod_tools"} {
+		if _, ok := schema.Properties[key]; !ok {
+			t.Errorf("Expected property %q in schema", key)
+		}
+	}
+
+	tool := schema.Defs["Tool"]
+	if strings.Join(tool.Properties["type"].Enum, ",") != strings.Join(testTypes, ",") {
+		t.Errorf("Expected type enum %v, got %v", testTypes, tool.Properties["type"].Enum)
+	}
+	if _, ok := tool.Properties["binaries"]; !ok {
+		t.Error("Expected binaries property in Tool schema")
+	}
+	if tool.AdditionalProperties {
+		t.Error("Expected additionalProperties to be false for Tool")
+	}
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// Workspace declares the member projects of a monorepo workspace.
+type Workspace struct {
+	Members []string `yaml:"members"` // Globs of member directories, relative to the workspace root
+}
+
+// Member is a project that belongs to a workspace.
+type Member struct {
+	Name   string // Directory relative to the workspace root
+	Path   string // Path of the member's configuration file
+	Config *Config
+}
+
+// MemberPaths returns the configuration files of all members of the workspace
+// rooted at root, sorted by directory. Directories matched by a member glob
+// without a configuration file are skipped.
+func (w *Workspace) MemberPaths(root string) ([]string, error) {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, pattern := range w.Members {
+		dirs, err := filepath.Glob(filepath.Join(root, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace member pattern %q: %w", pattern, err)
+		}
+		for _, dir := range dirs {
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				continue
+			}
+			if path := FindIn(dir); path != "" && !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// FindWorkspace returns the configuration file of the workspace that lists
+// dir as a member, or an empty string if dir is not part of a workspace.
+func FindWorkspace(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for parent := filepath.Dir(dir); ; parent = filepath.Dir(parent) {
+		if path := FindIn(parent); path != "" {
+			// Configurations that fail to load can't declare a workspace
+			if cfg, err := Load(path); err == nil && cfg.Workspace != nil {
+				members, err := cfg.Workspace.MemberPaths(parent)
+				if err != nil {
+					return "", err
+				}
+				for _, member := range members {
+					if filepath.Dir(member) == dir {
+						return path, nil
+					}
+				}
+			}
+		}
+		if filepath.Dir(parent) == parent {
+			return "", nil
+		}
+	}
+}
+
+// MemberView returns the configuration seen from a workspace member: the
+// workspace tools and env followed by the member's own. Like Union, it fails
+// if the member redefines a tool or env variable of the workspace root
+// differently, so a member sees exactly what box installs at the root.
+func (c *Config) MemberView(member *Config) (*Config, error) {
+	view := &Config{}
+	view.merge(c)
+	if err := view.checkMember(member); err != nil {
+		return nil, err
+	}
+	view.merge(member)
+	view.Workspace = nil
+	return view, nil
+}
+
+// Union returns the configuration installed at the workspace root: its own
+// tools and env followed by those of all members. A workspace has one
+// definition of each tool and env variable: members may add their own and
+// repeat those of the root or another member, but only identically.
+func (c *Config) Union(members []Member) (*Config, error) {
+	union := &Config{}
+	union.merge(c)
+
+	for _, member := range members {
+		if err := union.checkMember(member.Config); err != nil {
+			return nil, err
+		}
+		for _, t := range member.Config.Tools {
+			if !slices.ContainsFunc(union.Tools, func(existing Tool) bool { return existing.DisplayName() == t.DisplayName() }) {
+				union.Tools = append(union.Tools, t)
+			}
+		}
+		for k, v := range member.Config.Env {
+			if _, exists := union.Env[k]; exists {
+				continue
+			}
+			if union.Env == nil {
+				union.Env = make(map[string]string)
+			}
+			if union.EnvOrigin == nil {
+				union.EnvOrigin = make(map[string]string)
+			}
+			union.Env[k] = v
+			union.EnvOrigin[k] = member.Config.EnvOrigin[k]
+		}
+	}
+	return union, nil
+}
+
+// checkMember returns an error if member defines a tool or env variable of c
+// differently.
+func (c *Config) checkMember(member *Config) error {
+	for _, t := range member.Tools {
+		name := t.DisplayName()
+		for _, existing := range c.Tools {
+			if existing.DisplayName() == name && !sameTool(existing, t) {
+				return fmt.Errorf("tool %q is defined differently in %s and %s; define it once in the workspace root", name, existing.Origin, t.Origin)
+			}
+		}
+	}
+	for k, v := range member.Env {
+		if existing, exists := c.Env[k]; exists && existing != v {
+			return fmt.Errorf("env %s is defined differently in %s and %s; define it once in the workspace root", k, c.EnvOrigin[k], member.EnvOrigin[k])
+		}
+	}
+	return nil
+}
+
+// sameTool reports whether two tools install the same thing.
+func sameTool(a, b Tool) bool {
+	a.Origin, b.Origin = "", ""
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"box.yml": `
+workspace:
+  members: [services/*]
+tools:
+  - type: go
+    source: example.com/lint
+    version: v1.0.0
+env:
+  SHARED: root
+`,
+		"services/api/box.yml": `
+tools:
+  - type: go
+    source: example.com/gen
+    version: v1.0.0
+  - type: go
+    source: example.com/lint
+    version: v1.0.0
+env:
+  SERVICE: api
+`,
+		"services/web/box.yaml":    "tools:\n  - type: npm\n    source: cowsay\n",
+		"services/empty/README.md": "no configuration here\n",
+	})
+
+	cfg, err := Load(filepath.Join(dir, "box.yml"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Workspace == nil {
+		t.Fatal("expected workspace to be loaded")
+	}
+
+	paths, err := cfg.Workspace.MemberPaths(dir)
+	if err != nil {
+		t.Fatalf("MemberPaths failed: %v", err)
+	}
+	if len(paths) != 2 || paths[0] != filepath.Join(dir, "services", "api", "box.yml") || paths[1] != filepath.Join(dir, "services", "web", "box.yaml") {
+		t.Fatalf("unexpected member paths: %v", paths)
+	}
+
+	workspacePath, err := FindWorkspace(filepath.Join(dir, "services", "api"))
+	if err != nil || workspacePath != filepath.Join(dir, "box.yml") {
+		t.Errorf("expected workspace %s, got %q (%v)", filepath.Join(dir, "box.yml"), workspacePath, err)
+	}
+	if workspacePath, _ := FindWorkspace(filepath.Join(dir, "services", "empty")); workspacePath != "" {
+		t.Errorf("expected no workspace for a directory without configuration, got %s", workspacePath)
+	}
+
+	var members []Member
+	for _, path := range paths {
+		memberCfg, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		members = append(members, Member{Path: path, Config: memberCfg})
+	}
+
+	view, err := cfg.MemberView(members[0].Config)
+	if err != nil {
+		t.Fatalf("MemberView failed: %v", err)
+	}
+	if len(view.Tools) != 2 || view.Env["SHARED"] != "root" || view.Env["SERVICE"] != "api" || view.Workspace != nil {
+		t.Errorf("unexpected member view: %+v", view)
+	}
+
+	union, err := cfg.Union(members)
+	if err != nil {
+		t.Fatalf("Union failed: %v", err)
+	}
+	var names []string
+	for _, tool := range union.Tools {
+		names = append(names, tool.DisplayName())
+	}
+	if got := strings.Join(names, ","); got != "example.com/lint,example.com/gen,cowsay" {
+		t.Errorf("unexpected union: %s", got)
+	}
+	if union.Env["SHARED"] != "root" || union.Env["SERVICE"] != "api" {
+		t.Errorf("expected the union to include member env, got %v", union.Env)
+	}
+
+	// Members must not redefine a shared tool differently
+	members[0].Config.Tools[1].Version = "v2.0.0"
+	if _, err := cfg.Union(members); err == nil || !strings.Contains(err.Error(), "defined differently") {
+		t.Errorf("expected conflict error, got %v", err)
+	}
+}
+
+func TestWorkspaceMemberOverride(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"box.yml": `
+workspace:
+  members: [api]
+tools:
+  - type: go
+    source: example.com/lint
+    version: v1.0.0
+env:
+  LEVEL: info
+`,
+		"api/box.yml": "tools:\n  - type: go\n    source: example.com/lint\n    version: v2.0.0\n",
+		"web/box.yml": "env:\n  LEVEL: debug\n",
+	})
+
+	root, err := Load(filepath.Join(dir, "box.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"api", "web"} {
+		member, err := Load(filepath.Join(dir, name, "box.yml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The root and the member see the same conflict
+		if _, err := root.MemberView(member); err == nil || !strings.Contains(err.Error(), "defined differently") {
+			t.Errorf("%s: expected MemberView to reject the override, got %v", name, err)
+		}
+		if _, err := root.Union([]Member{{Name: name, Config: member}}); err == nil || !strings.Contains(err.Error(), "defined differently") {
+			t.Errorf("%s: expected Union to reject the override, got %v", name, err)
+		}
+	}
+}
@@ -18,34 +18,57 @@ var (
 	dimStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 )
 
-// Run executes a series of checks on the host environment to ensure required tools are present.
-func Run() {
-	fmt.Println(titleStyle.Render("Checking box host environment tools..."))
-
-	allFound := true
+// Result is the outcome of checking a single host runtime.
+type Result struct {
+	Name  string `json:"name"`
+	Found bool   `json:"found"`
+	Path  string `json:"path,omitempty"`
+}
 
+// Check looks up the runtimes used by the installers, and direnv, on the PATH.
+// The results are sorted by name.
+func Check() []Result {
 	// Get tools from central registry
 	var toolNames []string
-	toolMap := make(map[string]string)
 	for _, t := range installer.SupportedTools {
 		toolNames = append(toolNames, t.Name)
-		toolMap[t.Name] = t.Name
 	}
 	// Add direnv specifically as it's an integration, not a tool type
 	toolNames = append(toolNames, "direnv")
 	sort.Strings(toolNames)
 
+	results := make([]Result, 0, len(toolNames))
 	for _, tool := range toolNames {
 		path, err := exec.LookPath(tool)
-		if err != nil {
-			fmt.Printf("%s %-14s : Not found\n", errorStyle.Render("✗"), tool)
-			allFound = false
+		results = append(results, Result{Name: tool, Found: err == nil, Path: path})
+	}
+	return results
+}
+
+// Ready reports whether all runtimes were found.
+func Ready(results []Result) bool {
+	for _, r := range results {
+		if !r.Found {
+			return false
+		}
+	}
+	return true
+}
+
+// Run executes a series of checks on the host environment to ensure required tools are present.
+func Run() {
+	fmt.Println(titleStyle.Render("Checking box host environment tools..."))
+
+	results := Check()
+	for _, r := range results {
+		if !r.Found {
+			fmt.Printf("%s %-14s : Not found\n", errorStyle.Render("✗"), r.Name)
 		} else {
-			fmt.Printf("%s %-14s : %s\n", successStyle.Render("✓"), tool, dimStyle.Render(path))
+			fmt.Printf("%s %-14s : %s\n", successStyle.Render("✓"), r.Name, dimStyle.Render(r.Path))
 		}
 	}
 
-	if !allFound {
+	if !Ready(results) {
 		fmt.Println(lipgloss.NewStyle().MarginTop(1).Render("Some tools are missing. Please install them to use their respective package managers."))
 	} else {
 		fmt.Println(lipgloss.NewStyle().MarginTop(1).Foreground(lipgloss.Color("42")).Render("All external tools are ready. ✨"))
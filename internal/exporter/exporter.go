@@ -0,0 +1,220 @@
+// Package exporter converts box configurations into the formats of other tool managers.
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/importer"
+)
+
+const header = "Generated by box from box.yml. Do not edit by hand."
+
+// Format describes a supported export target.
+type Format struct {
+	// File is the default file name written by 'box export --write'.
+	File string
+	// Render converts the configuration into the target format.
+	Render func(cfg *config.Config, mappings *importer.Mappings) []byte
+}
+
+// Formats is the registry of supported export targets.
+var Formats = map[string]Format{
+	"mise":          {File: "mise.toml", Render: renderMise},
+	"tool-versions": {File: ".tool-versions", Render: renderToolVersions},
+	"nix":           {File: "flake.nix", Render: renderNix},
+}
+
+// FormatNames returns the sorted names of all supported formats.
+func FormatNames() []string {
+	names := make([]string, 0, len(Formats))
+	for name := range Formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Export renders the configuration in the given format.
+func Export(format string, cfg *config.Config, mappings *importer.Mappings) ([]byte, error) {
+	f, ok := Formats[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported export format: %s (supported: %s)", format, strings.Join(FormatNames(), ", "))
+	}
+	return f.Render(cfg, mappings), nil
+}
+
+// miseBackends maps box tool types to mise backends.
+var miseBackends = map[string]string{
+	"go":    "go",
+	"npm":   "npm",
+	"cargo": "cargo",
+	"uv":    "pipx",
+	"gem":   "gem",
+}
+
+func renderMise(cfg *config.Config, _ *importer.Mappings) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n\n[tools]\n", header)
+
+	exported := exportedTools{}
+	for _, t := range cfg.Tools {
+		backend, ok := miseBackends[t.Type]
+		if !ok {
+			fmt.Fprintf(&buf, "# %q: %s tools cannot be exported to mise\n", t.DisplayName(), t.Type)
+			continue
+		}
+
+		key := tomlQuote(backend + ":" + t.Source.String())
+		if owner, ok := exported.claim(key, t); !ok {
+			fmt.Fprintf(&buf, "# %q: %s\n", t.DisplayName(), owner)
+			continue
+		}
+		version := tomlQuote(plainVersion(t.Version))
+		switch {
+		case t.Type == "uv" && len(t.Args) > 0:
+			// mise installs pipx tools with uv and passes uvx_args through
+			fmt.Fprintf(&buf, "%s = { version = %s, uvx_args = %s }\n", key, version, tomlQuote(strings.Join(t.Args, " ")))
+		case len(t.Args) > 0:
+			fmt.Fprintf(&buf, "# args %q of the next tool cannot be exported to mise\n", strings.Join(t.Args, " "))
+			fmt.Fprintf(&buf, "%s = %s\n", key, version)
+		default:
+			fmt.Fprintf(&buf, "%s = %s\n", key, version)
+		}
+	}
+
+	if len(cfg.Env) > 0 {
+		buf.WriteString("\n[env]\n")
+		for _, k := range sortedKeys(cfg.Env) {
+			fmt.Fprintf(&buf, "%s = %s\n", tomlQuote(k), tomlQuote(cfg.Env[k]))
+		}
+	}
+	return buf.Bytes()
+}
+
+func renderToolVersions(cfg *config.Config, mappings *importer.Mappings) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", header)
+
+	exported := exportedTools{}
+	for _, t := range cfg.Tools {
+		name, _, ok := mappings.Reverse(t)
+		if !ok {
+			fmt.Fprintf(&buf, "# %q: no asdf plugin mapping for this %s tool\n", t.DisplayName(), t.Type)
+			continue
+		}
+		if owner, ok := exported.claim(name, t); !ok {
+			fmt.Fprintf(&buf, "# %q: %s\n", t.DisplayName(), owner)
+			continue
+		}
+		fmt.Fprintf(&buf, "%s %s\n", name, plainVersion(t.Version))
+	}
+
+	for _, k := range sortedKeys(cfg.Env) {
+		fmt.Fprintf(&buf, "# env %s cannot be exported to .tool-versions\n", k)
+	}
+	return buf.Bytes()
+}
+
+func renderNix(cfg *config.Config, mappings *importer.Mappings) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `# %s
+{
+  description = "Development environment generated by box";
+
+  inputs.nixpkgs.url = "github:NixOS/nixpkgs/nixos-unstable";
+  inputs.flake-utils.url = "github:numtide/flake-utils";
+
+  outputs = { nixpkgs, flake-utils, ... }:
+    flake-utils.lib.eachDefaultSystem (system:
+      let
+        pkgs = nixpkgs.legacyPackages.${system};
+      in
+      {
+        devShells.default = pkgs.mkShell {
+          packages = [
+`, header)
+
+	exported := exportedTools{}
+	for _, t := range cfg.Tools {
+		_, mapping, ok := mappings.Reverse(t)
+		if !ok || mapping.Nix == "" {
+			fmt.Fprintf(&buf, "            # %q: no nixpkgs mapping for this %s tool\n", t.DisplayName(), t.Type)
+			continue
+		}
+		if owner, ok := exported.claim(mapping.Nix, t); !ok {
+			fmt.Fprintf(&buf, "            # %q: %s\n", t.DisplayName(), owner)
+			continue
+		}
+		// nixpkgs provides a single version per channel, so record the requested one
+		fmt.Fprintf(&buf, "            pkgs.%s # %s %s\n", mapping.Nix, nixComment(t.Source.String()), plainVersion(t.Version))
+	}
+
+	buf.WriteString("          ];\n")
+	if len(cfg.Env) > 0 {
+		buf.WriteString("\n          env = {\n")
+		for _, k := range sortedKeys(cfg.Env) {
+			fmt.Fprintf(&buf, "            %s = %s;\n", nixQuote(k), nixQuote(cfg.Env[k]))
+		}
+		buf.WriteString("          };\n")
+	}
+	buf.WriteString("        };\n      });\n}\n")
+	return buf.Bytes()
+}
+
+// exportedTools records the tool exported under each key of a format. The
+// formats hold one version per tool, so another tool with the same key, such
+// as a bin_name_template variant of the same source, is written as a comment.
+type exportedTools map[string]config.Tool
+
+// claim records t under key and reports whether the key was free. Otherwise
+// it returns a note naming the tool exported under key.
+func (e exportedTools) claim(key string, t config.Tool) (string, bool) {
+	if first, ok := e[key]; ok {
+		return fmt.Sprintf("version %s is not exported, because %q exports %s as the only version",
+			plainVersion(t.Version), first.DisplayName(), plainVersion(first.Version)), false
+	}
+	e[key] = t
+	return "", true
+}
+
+// plainVersion returns the version without a leading "v", or "latest" if unset.
+func plainVersion(version string) string {
+	if version == "" {
+		return "latest"
+	}
+	if len(version) > 1 && version[0] == 'v' && version[1] >= '0' && version[1] <= '9' {
+		return version[1:]
+	}
+	return version
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// tomlQuote quotes s as a TOML basic string. JSON string escaping is a valid subset.
+func tomlQuote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// nixQuote quotes s as a Nix string, escaping interpolation.
+func nixQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", `\${`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// nixComment makes s safe for use in a single line comment.
+func nixComment(s string) string {
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,148 @@
+package exporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/importer"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{
+		Tools: []config.Tool{
+			{Type: "go", Source: config.Source{"github.com/go-task/task/v3/cmd/task"}, Version: "v3.40.0"},
+			{Type: "uv", Source: config.Source{"mkdocs"}, Version: "1.6.1", Args: []string{"--with", "mkdocs-material"}},
+			{Type: "cargo", Source: config.Source{"jj-cli"}},
+			{Type: "go", Source: config.Source{"example.com/internal/tool"}, Version: "latest"},
+			{Type: "script", Alias: "installer", Source: config.Source{"echo one", "echo two"}},
+		},
+		Env: map[string]string{"APP_DEBUG": "true", "GREETING": `hello "${USER}"`},
+	}
+}
+
+func render(t *testing.T, format string) string {
+	t.Helper()
+	mappings, err := importer.LoadMappings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Export(format, testConfig(), mappings)
+	if err != nil {
+		t.Fatalf("Export(%s) failed: %v", format, err)
+	}
+	// Output must be deterministic so it can be committed
+	again, _ := Export(format, testConfig(), mappings)
+	if string(out) != string(again) {
+		t.Errorf("Export(%s) is not deterministic", format)
+	}
+	return string(out)
+}
+
+func assertContains(t *testing.T, content string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(content, w) {
+			t.Errorf("expected output to contain %q:\n%s", w, content)
+		}
+	}
+}
+
+func TestExportMise(t *testing.T) {
+	out := render(t, "mise")
+	assertContains(t, out,
+		`"go:github.com/go-task/task/v3/cmd/task" = "3.40.0"`,
+		`"pipx:mkdocs" = { version = "1.6.1", uvx_args = "--with mkdocs-material" }`,
+		`"cargo:jj-cli" = "latest"`,
+		`# "installer": script tools cannot be exported to mise`,
+		"[env]\n\"APP_DEBUG\" = \"true\"\n",
+	)
+}
+
+func TestExportToolVersions(t *testing.T) {
+	out := render(t, "tool-versions")
+	assertContains(t, out,
+		"task 3.40.0\n",
+		"mkdocs 1.6.1\n",
+		"jj latest\n",
+		`# "example.com/internal/tool": no asdf plugin mapping`,
+		"# env APP_DEBUG cannot be exported",
+	)
+}
+
+func TestExportNix(t *testing.T) {
+	out := render(t, "nix")
+	assertContains(t, out,
+		"pkgs.go-task # github.com/go-task/task/v3/cmd/task 3.40.0",
+		"pkgs.jujutsu # jj-cli latest",
+		`# "installer": no nixpkgs mapping for this script tool`,
+		`"GREETING" = "hello \"\${USER}\"";`,
+	)
+}
+
+func TestExportVariants(t *testing.T) {
+	mappings, err := importer.LoadMappings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Tools: []config.Tool{
+		{Type: "go", Source: config.Source{"golang.org/x/tools/gopls"}, Version: "v0.16.2", BinNameTemplate: "{{.Name}}-{{.Version}}"},
+		{Type: "go", Source: config.Source{"golang.org/x/tools/gopls"}, Version: "v0.15.3", BinNameTemplate: "{{.Name}}-{{.Version}}"},
+	}}
+
+	tests := map[string]string{
+		"mise":          `"go:golang.org/x/tools/gopls" = "0.16.2"`,
+		"tool-versions": "gopls 0.16.2",
+		"nix":           "pkgs.gopls #",
+	}
+	for format, entry := range tests {
+		out, err := Export(format, cfg, mappings)
+		if err != nil {
+			t.Fatalf("Export(%s) failed: %v", format, err)
+		}
+		if n := strings.Count(string(out), entry); n != 1 {
+			t.Errorf("%s: expected one entry %q, got %d:\n%s", format, entry, n, out)
+		}
+		assertContains(t, string(out), `version 0.15.3 is not exported, because "golang.org/x/tools/gopls@v0.16.2" exports 0.16.2 as the only version`)
+	}
+}
+
+func TestExportUnsupportedFormat(t *testing.T) {
+	if _, err := Export("brew", testConfig(), nil); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestExportLeavesOutLocalTools(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"box.yml":        "tools:\n  - type: go\n    source: github.com/go-task/task/v3/cmd/task\n    version: v3.40.0\n",
+		config.LocalFile: "tools:\n  - type: npm\n    source: private-tool\nenv:\n  SECRET_HOST: internal\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := config.LoadCommitted(filepath.Join(dir, "box.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mappings, err := importer.LoadMappings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Export("mise", cfg, mappings)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	assertContains(t, string(out), "go-task")
+	for _, local := range []string{"private-tool", "SECRET_HOST"} {
+		if strings.Contains(string(out), local) {
+			t.Errorf("expected %s from box.local.yml to be left out:\n%s", local, out)
+		}
+	}
+}
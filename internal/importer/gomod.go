@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// GoModTools reads the tool directives (Go 1.24+) of the go.mod file in dir and
+// returns them as go tools pinned to the versions selected by the module graph.
+func GoModTools(dir string) (*Result, error) {
+	path := filepath.Join(dir, "go.mod")
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	return parseGoMod(data, path, nil)
+}
+
+// SyncGoModTools merges the tool directives of the go.mod file in dir into cfg.
+// Tools already defined with the same source get the version from go.mod,
+// all others are appended.
+func SyncGoModTools(cfg *config.Config, dir string) error {
+	result, err := GoModTools(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read go.mod tools: %w", err)
+	}
+
+	for _, modTool := range result.Tools {
+		found := false
+		for i := range cfg.Tools {
+			t := &cfg.Tools[i]
+			if t.Type == "go" && t.Source.String() == modTool.Source.String() {
+				t.Version = modTool.Version
+				found = true
+			}
+		}
+		if !found {
+			modTool.Origin = filepath.Join(dir, "go.mod")
+			cfg.Tools = append(cfg.Tools, modTool)
+		}
+	}
+	return nil
+}
+
+// parseGoMod converts the tool directives of a go.mod file into go tools.
+func parseGoMod(data []byte, path string, _ *Mappings) (*Result, error) {
+	f, err := modfile.Parse(path, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sums := readGoSum(filepath.Join(filepath.Dir(path), "go.sum"))
+	replaced := make(map[string]bool)
+	for _, r := range f.Replace {
+		replaced[r.Old.Path] = true
+	}
+
+	result := &Result{}
+	for _, tool := range f.Tool {
+		pkg := tool.Path
+
+		if f.Module != nil && isWithinModule(pkg, f.Module.Mod.Path) {
+			result.Unmapped = append(result.Unmapped, Unmapped{Name: pkg, Reason: "tool is part of the main module"})
+			continue
+		}
+
+		mod := owningModule(f, pkg)
+		if mod == nil {
+			result.Unmapped = append(result.Unmapped, Unmapped{Name: pkg, Reason: "no require directive provides this package"})
+			continue
+		}
+		if replaced[mod.Mod.Path] {
+			result.Unmapped = append(result.Unmapped, Unmapped{Name: pkg, Reason: fmt.Sprintf("module %s is replaced, go install would ignore the replacement", mod.Mod.Path)})
+			continue
+		}
+		if sums != nil && !sums[mod.Mod.Path+" "+mod.Mod.Version] {
+			result.Unmapped = append(result.Unmapped, Unmapped{Name: pkg, Reason: fmt.Sprintf("%s@%s is missing from go.sum, run 'go mod tidy'", mod.Mod.Path, mod.Mod.Version)})
+			continue
+		}
+
+		result.Tools = append(result.Tools, config.Tool{
+			Type:    "go",
+			Source:  config.Source{pkg},
+			Version: mod.Mod.Version,
+		})
+	}
+	return result, nil
+}
+
+// owningModule returns the required module with the longest path containing pkg.
+func owningModule(f *modfile.File, pkg string) *modfile.Require {
+	var best *modfile.Require
+	for _, r := range f.Require {
+		if isWithinModule(pkg, r.Mod.Path) && (best == nil || len(r.Mod.Path) > len(best.Mod.Path)) {
+			best = r
+		}
+	}
+	return best
+}
+
+func isWithinModule(pkg, modPath string) bool {
+	return pkg == modPath || strings.HasPrefix(pkg, modPath+"/")
+}
+
+// readGoSum returns the set of "module version" pairs listed in go.sum, or nil if it doesn't exist.
+func readGoSum(path string) map[string]bool {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil
+	}
+
+	sums := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		// Entries for the go.mod file only carry a "/go.mod" suffix on the version
+		sums[fields[0]+" "+fields[1]] = true
+	}
+	return sums
+}
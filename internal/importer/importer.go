@@ -0,0 +1,236 @@
+// Package importer converts tool definitions from other tool managers into box tools.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// Unmapped describes an entry that could not be converted into a box tool.
+type Unmapped struct {
+	Name   string
+	Reason string
+}
+
+// Result holds the outcome of an import.
+type Result struct {
+	Tools    []config.Tool
+	Unmapped []Unmapped
+}
+
+// Format describes a supported import source.
+type Format struct {
+	// Files are the default file names looked up in the project directory, in order of preference.
+	Files []string
+	// Parse converts the file contents into box tools.
+	Parse func(data []byte, path string, mappings *Mappings) (*Result, error)
+}
+
+// Formats is the registry of supported import sources.
+var Formats = map[string]Format{
+	"tool-versions": {Files: []string{".tool-versions"}, Parse: parseToolVersions},
+	"mise":          {Files: []string{"mise.toml", ".mise.toml", ".config/mise.toml"}, Parse: parseMise},
+	"aqua":          {Files: []string{"aqua.yaml", "aqua.yml", ".aqua.yaml"}, Parse: parseAqua},
+	"go.mod":        {Files: []string{"go.mod"}, Parse: parseGoMod},
+	"package.json":  {Files: []string{"package.json"}, Parse: parsePackageJSON},
+	"pyproject":     {Files: []string{"pyproject.toml"}, Parse: parsePyproject},
+}
+
+// FormatNames returns the sorted names of all supported formats.
+func FormatNames() []string {
+	names := make([]string, 0, len(Formats))
+	for name := range Formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultPath returns the first existing default file for the format in dir.
+func DefaultPath(format, dir string) (string, error) {
+	f, ok := Formats[format]
+	if !ok {
+		return "", fmt.Errorf("unsupported import format: %s (supported: %s)", format, strings.Join(FormatNames(), ", "))
+	}
+	for _, name := range f.Files {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no %s file found in %s (looked for %s)", format, dir, strings.Join(f.Files, ", "))
+}
+
+// Import reads the file at path in the given format and converts it into box tools.
+func Import(format, path string, mappings *Mappings) (*Result, error) {
+	f, ok := Formats[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported import format: %s (supported: %s)", format, strings.Join(FormatNames(), ", "))
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := f.Parse(data, path, mappings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return result, nil
+}
+
+// add converts a named tool using the mapping table and records it in the result.
+func (r *Result) add(mappings *Mappings, name, version string) {
+	if mappings.IsRuntime(name) {
+		r.Unmapped = append(r.Unmapped, Unmapped{Name: name, Reason: "language runtime, install it on the host"})
+		return
+	}
+	mapping, ok := mappings.Lookup(name)
+	if !ok {
+		r.Unmapped = append(r.Unmapped, Unmapped{Name: name, Reason: "no mapping to a box tool type"})
+		return
+	}
+	r.Tools = append(r.Tools, mapping.Tool(name, version))
+}
+
+// parseToolVersions parses an asdf .tool-versions file.
+func parseToolVersions(data []byte, _ string, mappings *Mappings) (*Result, error) {
+	result := &Result{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		version := ""
+		if len(fields) > 1 {
+			// asdf allows fallback versions; the first one is preferred
+			version = fields[1]
+		}
+		result.add(mappings, fields[0], version)
+	}
+	return result, scanner.Err()
+}
+
+// miseBackends maps mise backend prefixes to box tool types.
+var miseBackends = map[string]string{
+	"go":    "go",
+	"npm":   "npm",
+	"cargo": "cargo",
+	"pipx":  "uv",
+	"gem":   "gem",
+}
+
+// parseMise parses the [tools] table of a mise.toml file.
+func parseMise(data []byte, _ string, mappings *Mappings) (*Result, error) {
+	var doc struct {
+		Tools map[string]any `toml:"tools"`
+	}
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(doc.Tools))
+	for name := range doc.Tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &Result{}
+	for _, name := range names {
+		version := miseVersion(doc.Tools[name])
+
+		backend, target, hasBackend := strings.Cut(name, ":")
+		if !hasBackend {
+			result.add(mappings, name, version)
+			continue
+		}
+
+		if toolType, ok := miseBackends[backend]; ok {
+			mapping := Mapping{Type: toolType, Source: target}
+			result.Tools = append(result.Tools, mapping.Tool("", version))
+			continue
+		}
+
+		switch backend {
+		case "core", "asdf":
+			result.add(mappings, target, version)
+		case "aqua", "ubi", "github":
+			if toolName, mapping, ok := mappings.LookupAqua(target); ok {
+				result.Tools = append(result.Tools, mapping.Tool(toolName, version))
+			} else {
+				result.Unmapped = append(result.Unmapped, Unmapped{Name: name, Reason: "no mapping to a box tool type"})
+			}
+		default:
+			result.Unmapped = append(result.Unmapped, Unmapped{Name: name, Reason: fmt.Sprintf("unsupported mise backend %q", backend)})
+		}
+	}
+	return result, nil
+}
+
+// miseVersion extracts the version from the different value forms mise accepts.
+func miseVersion(v any) string {
+	switch val := v.(type) {
+	case string:
+		if _, rest, ok := strings.Cut(val, "prefix:"); ok {
+			return rest
+		}
+		return val
+	case []any:
+		if len(val) > 0 {
+			return miseVersion(val[0])
+		}
+	case map[string]any:
+		return miseVersion(val["version"])
+	}
+	return ""
+}
+
+// parseAqua parses the packages of an aqua.yaml file.
+func parseAqua(data []byte, _ string, mappings *Mappings) (*Result, error) {
+	var doc struct {
+		Packages []struct {
+			Name    string `yaml:"name"`
+			Version string `yaml:"version"`
+			Import  string `yaml:"import"`
+		} `yaml:"packages"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for _, pkg := range doc.Packages {
+		if pkg.Import != "" {
+			result.Unmapped = append(result.Unmapped, Unmapped{Name: pkg.Import, Reason: "imported package files are not followed"})
+			continue
+		}
+		name, version, _ := strings.Cut(pkg.Name, "@")
+		if pkg.Version != "" {
+			version = pkg.Version
+		}
+
+		toolName, mapping, ok := mappings.LookupAqua(name)
+		if !ok {
+			result.Unmapped = append(result.Unmapped, Unmapped{Name: name, Reason: "no mapping to a box tool type"})
+			continue
+		}
+		result.Tools = append(result.Tools, mapping.Tool(toolName, version))
+	}
+	return result, nil
+}
@@ -0,0 +1,374 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+func loadTestMappings(t *testing.T) *Mappings {
+	t.Helper()
+	mappings, err := LoadMappings()
+	if err != nil {
+		t.Fatalf("LoadMappings failed: %v", err)
+	}
+	return mappings
+}
+
+func findTool(tools []config.Tool, name string) *config.Tool {
+	for i := range tools {
+		if tools[i].DisplayName() == name || tools[i].Source.String() == name {
+			return &tools[i]
+		}
+	}
+	return nil
+}
+
+func TestParseToolVersions(t *testing.T) {
+	data := []byte(`# runtimes
+golang 1.24.2
+golangci-lint 1.60.1 1.59.0
+ripgrep 14.1.0
+unknown-tool 1.0.0
+`)
+	result, err := parseToolVersions(data, ".tool-versions", loadTestMappings(t))
+	if err != nil {
+		t.Fatalf("parseToolVersions failed: %v", err)
+	}
+
+	lint := findTool(result.Tools, "github.com/golangci/golangci-lint/cmd/golangci-lint")
+	if lint == nil {
+		t.Fatalf("expected golangci-lint v1 source, got %+v", result.Tools)
+	}
+	if lint.Type != "go" || lint.Version != "v1.60.1" {
+		t.Errorf("unexpected golangci-lint tool: %+v", lint)
+	}
+
+	rg := findTool(result.Tools, "ripgrep")
+	if rg == nil || rg.Type != "cargo" || rg.Version != "14.1.0" || len(rg.Binaries) != 1 || rg.Binaries[0] != "rg" {
+		t.Errorf("unexpected ripgrep tool: %+v", rg)
+	}
+
+	if len(result.Unmapped) != 2 {
+		t.Errorf("expected golang and unknown-tool to be unmapped, got %+v", result.Unmapped)
+	}
+}
+
+func TestParseMise(t *testing.T) {
+	data := []byte(`
+[tools]
+go = "1.24"
+"npm:prettier" = "3.3.3"
+"go:golang.org/x/tools/gopls" = "latest"
+"pipx:black" = { version = "24.3.0" }
+"aqua:jj-vcs/jj" = ["0.20.0"]
+"spm:foo/bar" = "1.0"
+`)
+	result, err := parseMise(data, "mise.toml", loadTestMappings(t))
+	if err != nil {
+		t.Fatalf("parseMise failed: %v", err)
+	}
+
+	tests := []struct {
+		name, toolType, version string
+	}{
+		{"prettier", "npm", "3.3.3"},
+		{"golang.org/x/tools/gopls", "go", "latest"},
+		{"black", "uv", "24.3.0"},
+		{"jj", "cargo", "0.20.0"},
+	}
+	for _, tt := range tests {
+		tool := findTool(result.Tools, tt.name)
+		if tool == nil {
+			t.Errorf("expected tool %s, got %+v", tt.name, result.Tools)
+			continue
+		}
+		if tool.Type != tt.toolType || tool.Version != tt.version {
+			t.Errorf("tool %s: got type %s version %s, want %s %s", tt.name, tool.Type, tool.Version, tt.toolType, tt.version)
+		}
+	}
+
+	if len(result.Unmapped) != 2 {
+		t.Errorf("expected go runtime and spm backend to be unmapped, got %+v", result.Unmapped)
+	}
+}
+
+func TestParseAqua(t *testing.T) {
+	data := []byte(`
+registries:
+  - type: standard
+    ref: v4.0.0
+packages:
+  - name: golangci/golangci-lint@v2.9.0
+  - name: go-task/task
+    version: v3.40.0
+  - name: unknown/tool@v1.0.0
+  - import: aqua/*.yaml
+`)
+	result, err := parseAqua(data, "aqua.yaml", loadTestMappings(t))
+	if err != nil {
+		t.Fatalf("parseAqua failed: %v", err)
+	}
+
+	lint := findTool(result.Tools, "github.com/golangci/golangci-lint/v2/cmd/golangci-lint")
+	if lint == nil || lint.Version != "v2.9.0" {
+		t.Errorf("unexpected golangci-lint tool: %+v", result.Tools)
+	}
+	task := findTool(result.Tools, "github.com/go-task/task/v3/cmd/task")
+	if task == nil || task.Version != "v3.40.0" {
+		t.Errorf("unexpected task tool: %+v", result.Tools)
+	}
+	if len(result.Unmapped) != 2 {
+		t.Errorf("expected 2 unmapped entries, got %+v", result.Unmapped)
+	}
+}
+
+func TestLoadMappingsExtension(t *testing.T) {
+	extra := filepath.Join(t.TempDir(), "mappings.yml")
+	content := []byte(`
+tools:
+  my-linter:
+    type: go
+    source: example.com/my-linter
+`)
+	if err := os.WriteFile(extra, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	mappings, err := LoadMappings(extra)
+	if err != nil {
+		t.Fatalf("LoadMappings failed: %v", err)
+	}
+	if _, ok := mappings.Lookup("my-linter"); !ok {
+		t.Error("expected extension mapping to be loaded")
+	}
+	if _, ok := mappings.Lookup("golangci-lint"); !ok {
+		t.Error("expected built-in mappings to be kept")
+	}
+}
+
+func TestLookupAquaDeterministic(t *testing.T) {
+	mappings := &Mappings{Tools: map[string]Mapping{
+		"lint-b": {Type: "go", Source: "example.com/lint", Aqua: []string{"example/lint"}},
+		"lint-a": {Type: "go", Source: "example.com/lint", Aqua: []string{"example/lint"}},
+		"lint-c": {Type: "go", Source: "example.com/lint", Aqua: []string{"example/lint"}},
+	}}
+	for range 20 {
+		if name, _, ok := mappings.LookupAqua("Example/Lint"); !ok || name != "lint-a" {
+			t.Fatalf("expected the first mapping by name, got %q (%v)", name, ok)
+		}
+	}
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := []struct {
+		toolType, version, expected string
+	}{
+		{"go", "1.2.3", "v1.2.3"},
+		{"go", "", "latest"},
+		{"go", "latest", "latest"},
+		{"uv", "latest", ""},
+		{"cargo", "v1.0.0", "1.0.0"},
+		{"npm", "latest", "latest"},
+		{"gem", "system", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeVersion(tt.toolType, tt.version); got != tt.expected {
+			t.Errorf("normalizeVersion(%q, %q) = %q, want %q", tt.toolType, tt.version, got, tt.expected)
+		}
+	}
+}
+
+func TestParseGoMod(t *testing.T) {
+	dir := t.TempDir()
+	goMod := []byte(`module example.com/project
+
+go 1.24
+
+tool (
+	example.com/project/cmd/gen
+	github.com/golangci/golangci-lint/v2/cmd/golangci-lint
+	golang.org/x/tools/cmd/stringer
+	github.com/local/fork/cmd/fork
+)
+
+require (
+	github.com/golangci/golangci-lint/v2 v2.9.0 // indirect
+	golang.org/x/tools v0.30.0 // indirect
+	github.com/local/fork v1.0.0 // indirect
+)
+
+replace github.com/local/fork => ../fork
+`)
+	goSum := []byte(`github.com/golangci/golangci-lint/v2 v2.9.0 h1:abc=
+github.com/golangci/golangci-lint/v2 v2.9.0/go.mod h1:def=
+golang.org/x/tools v0.30.0 h1:ghi=
+`)
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), goMod, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), goSum, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := GoModTools(dir)
+	if err != nil {
+		t.Fatalf("GoModTools failed: %v", err)
+	}
+
+	if len(result.Tools) != 2 {
+		t.Fatalf("expected 2 tools, got %+v", result.Tools)
+	}
+	lint := findTool(result.Tools, "github.com/golangci/golangci-lint/v2/cmd/golangci-lint")
+	if lint == nil || lint.Type != "go" || lint.Version != "v2.9.0" {
+		t.Errorf("unexpected golangci-lint tool: %+v", lint)
+	}
+	stringer := findTool(result.Tools, "golang.org/x/tools/cmd/stringer")
+	if stringer == nil || stringer.Version != "v0.30.0" {
+		t.Errorf("unexpected stringer tool: %+v", stringer)
+	}
+	if len(result.Unmapped) != 2 {
+		t.Errorf("expected main module and replaced tools to be unmapped, got %+v", result.Unmapped)
+	}
+
+	cfg := &config.Config{Tools: []config.Tool{
+		{Type: "go", Source: config.Source{"golang.org/x/tools/cmd/stringer"}, Version: "v0.1.0"},
+	}}
+	if err := SyncGoModTools(cfg, dir); err != nil {
+		t.Fatalf("SyncGoModTools failed: %v", err)
+	}
+	if len(cfg.Tools) != 2 || cfg.Tools[0].Version != "v0.30.0" {
+		t.Errorf("expected stringer to be updated and golangci-lint appended, got %+v", cfg.Tools)
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParsePackageJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"package.json": `{
+  "devDependencies": {
+    "prettier": "^3.3.0",
+    "@biomejs/biome": "1.9.0",
+    "typescript": "^5.6.0",
+    "@types/node": "^22.0.0",
+    "local-tool": "file:../local-tool"
+  }
+}`,
+		"package-lock.json": `{
+  "lockfileVersion": 3,
+  "packages": {
+    "node_modules/prettier": {"version": "3.3.3", "bin": {"prettier": "bin/prettier.cjs"}},
+    "node_modules/@biomejs/biome": {"version": "1.9.0", "bin": "bin/biome"},
+    "node_modules/@types/node": {"version": "22.5.0"}
+  }
+}`,
+		"node_modules/typescript/package.json": `{"version": "5.6.2", "bin": {"tsc": "bin/tsc", "tsserver": "bin/tsserver"}}`,
+	})
+
+	result, err := Import("package.json", filepath.Join(dir, "package.json"), loadTestMappings(t))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	tests := []struct {
+		name, version string
+		binaries      []string
+	}{
+		{"prettier", "3.3.3", []string{"prettier"}},
+		{"@biomejs/biome", "1.9.0", []string{"biome"}},
+		{"typescript", "5.6.2", []string{"tsc", "tsserver"}},
+	}
+	for _, tt := range tests {
+		tool := findTool(result.Tools, tt.name)
+		if tool == nil {
+			t.Errorf("expected tool %s, got %+v", tt.name, result.Tools)
+			continue
+		}
+		if tool.Type != "npm" || tool.Version != tt.version || strings.Join(tool.Binaries, ",") != strings.Join(tt.binaries, ",") {
+			t.Errorf("unexpected tool %s: %+v", tt.name, tool)
+		}
+	}
+	if len(result.Unmapped) != 2 {
+		t.Errorf("expected @types/node and local-tool to be unmapped, got %+v", result.Unmapped)
+	}
+}
+
+func TestParsePyproject(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pyproject.toml": `
+[project]
+name = "example"
+
+[dependency-groups]
+dev = ["ruff>=0.5", "mypy==1.10.0", "types-requests", {include-group = "docs"}]
+docs = ["mkdocs-material"]
+
+[tool.black]
+line-length = 100
+`,
+		"uv.lock": `
+version = 1
+
+[[package]]
+name = "ruff"
+version = "0.6.9"
+
+[[package]]
+name = "black"
+version = "24.3.0"
+`,
+		".venv/lib/python3.12/site-packages/ruff-0.6.9.dist-info/entry_points.txt": "",
+		".venv/lib/python3.12/site-packages/mypy-1.10.0.dist-info/entry_points.txt": `[console_scripts]
+dmypy = mypy.dmypy.client:console_entry
+mypy = mypy.__main__:console_entry
+`,
+		".venv/lib/python3.12/site-packages/types_requests-2.32.0.dist-info/RECORD": "",
+		".venv/lib/python3.12/site-packages/black-24.3.0.dist-info/entry_points.txt": `[console_scripts]
+black = black:patched_main
+`,
+	})
+
+	result, err := Import("pyproject", filepath.Join(dir, "pyproject.toml"), loadTestMappings(t))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	mypy := findTool(result.Tools, "mypy")
+	if mypy == nil || mypy.Type != "uv" || mypy.Version != "1.10.0" || strings.Join(mypy.Binaries, ",") != "dmypy,mypy" {
+		t.Errorf("unexpected mypy tool: %+v", mypy)
+	}
+	black := findTool(result.Tools, "black")
+	if black == nil || black.Version != "24.3.0" || strings.Join(black.Binaries, ",") != "black" {
+		t.Errorf("unexpected black tool: %+v", black)
+	}
+	// Not installed in .venv, so binaries are left to detection
+	if docs := findTool(result.Tools, "mkdocs-material"); docs == nil || len(docs.Binaries) != 0 {
+		t.Errorf("unexpected mkdocs-material tool: %+v", docs)
+	}
+
+	unmapped := map[string]bool{}
+	for _, u := range result.Unmapped {
+		unmapped[u.Name] = true
+	}
+	// ruff has an empty entry_points.txt here and types-requests has none
+	if !unmapped["ruff"] || !unmapped["types-requests"] {
+		t.Errorf("expected ruff and types-requests to be reported as libraries, got %+v", result.Unmapped)
+	}
+}
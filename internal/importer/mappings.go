@@ -0,0 +1,193 @@
+package importer
+
+import (
+	_ "embed" // for the default mapping table
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+//go:embed mappings.yml
+var defaultMappings []byte
+
+// Mapping describes how a tool known to another tool manager maps to box.
+type Mapping struct {
+	Type         string            `yaml:"type"`
+	Source       string            `yaml:"source"`
+	Binaries     []string          `yaml:"binaries,omitempty"`
+	MajorSources map[string]string `yaml:"major_sources,omitempty"` // Source overrides per major version
+	Aqua         []string          `yaml:"aqua,omitempty"`          // aqua registry package names
+	Nix          string            `yaml:"nix,omitempty"`           // nixpkgs attribute
+}
+
+// Mappings is the table used to translate foreign tool names into box tools.
+type Mappings struct {
+	Tools    map[string]Mapping `yaml:"tools"`
+	Runtimes []string           `yaml:"runtimes"`
+}
+
+// LoadMappings loads the built-in mapping table and merges the given files on top of it.
+// Entries in later files override earlier ones.
+func LoadMappings(files ...string) (*Mappings, error) {
+	m := &Mappings{Tools: make(map[string]Mapping)}
+	if err := m.merge(defaultMappings, "built-in mappings"); err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return nil, err
+		}
+		if err := m.merge(data, file); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *Mappings) merge(data []byte, name string) error {
+	var extra Mappings
+	if err := yaml.Unmarshal(data, &extra); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	for k, v := range extra.Tools {
+		m.Tools[k] = v
+	}
+	m.Runtimes = append(m.Runtimes, extra.Runtimes...)
+	return nil
+}
+
+// Lookup finds the mapping for a tool name.
+func (m *Mappings) Lookup(name string) (Mapping, bool) {
+	mapping, ok := m.Tools[name]
+	return mapping, ok
+}
+
+// LookupAqua finds the mapping for an aqua registry package name (e.g. "golangci/golangci-lint").
+func (m *Mappings) LookupAqua(pkg string) (string, Mapping, bool) {
+	for _, name := range m.names() {
+		mapping := m.Tools[name]
+		for _, a := range mapping.Aqua {
+			if strings.EqualFold(a, pkg) {
+				return name, mapping, true
+			}
+		}
+	}
+	return "", Mapping{}, false
+}
+
+// names returns the tool names in a stable order, so lookups that could match
+// several mappings are deterministic.
+func (m *Mappings) names() []string {
+	names := make([]string, 0, len(m.Tools))
+	for name := range m.Tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Reverse finds the mapping whose type and source match a box tool.
+// It returns the foreign tool name along with the mapping.
+func (m *Mappings) Reverse(tool config.Tool) (string, Mapping, bool) {
+	source := tool.Source.String()
+	for _, name := range m.names() {
+		mapping := m.Tools[name]
+		if mapping.Type != tool.Type {
+			continue
+		}
+		if mapping.Source == source {
+			return name, mapping, true
+		}
+		for _, alt := range mapping.MajorSources {
+			if alt == source {
+				return name, mapping, true
+			}
+		}
+	}
+	return "", Mapping{}, false
+}
+
+// IsRuntime reports whether name refers to a language runtime rather than a tool.
+func (m *Mappings) IsRuntime(name string) bool {
+	for _, r := range m.Runtimes {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Tool builds a box tool definition from a mapping and the requested version.
+func (mp Mapping) Tool(name, version string) config.Tool {
+	source := mp.Source
+	if alt, ok := mp.MajorSources[majorVersion(version)]; ok {
+		source = alt
+	}
+	t := config.Tool{
+		Type:     mp.Type,
+		Source:   config.Source{source},
+		Version:  normalizeVersion(mp.Type, version),
+		Binaries: mp.Binaries,
+	}
+	// Use the foreign name as alias when it differs from what box would display
+	if name != "" && name != source && name != detectName(source) {
+		t.Alias = name
+	}
+	return t
+}
+
+// normalizeVersion converts a version from another tool manager into the format
+// expected by the given box installer type.
+func normalizeVersion(toolType, version string) string {
+	version = strings.TrimSpace(version)
+	if version == "system" {
+		version = ""
+	}
+
+	switch toolType {
+	case "go":
+		if version == "" {
+			// go install requires an explicit version outside of a module
+			return "latest"
+		}
+		if version[0] >= '0' && version[0] <= '9' {
+			return "v" + version
+		}
+		return version
+	case "npm":
+		return version
+	default:
+		// Other installers don't understand "latest" and install it by default
+		if version == "latest" {
+			return ""
+		}
+		return strings.TrimPrefix(version, "v")
+	}
+}
+
+func majorVersion(version string) string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if idx := strings.Index(version, "."); idx != -1 {
+		version = version[:idx]
+	}
+	return version
+}
+
+// detectName mirrors the binary name detection of the installers so that
+// aliases are only added when they carry information.
+func detectName(source string) string {
+	parts := strings.Split(source, "/")
+	last := parts[len(parts)-1]
+	if len(parts) > 1 && len(last) >= 2 && last[0] == 'v' && strings.Trim(last[1:], "0123456789") == "" {
+		last = parts[len(parts)-2]
+	}
+	return last
+}
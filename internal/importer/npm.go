@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// npmPackage holds the fields of a package.json or package-lock.json entry used by the importer.
+type npmPackage struct {
+	Version         string            `json:"version"`
+	Bin             json.RawMessage   `json:"bin"`
+	DevDependencies map[string]string `json:"devDependencies"`
+}
+
+// binaries returns the executables a package exposes via its bin field.
+func (p npmPackage) binaries(name string) []string {
+	if len(p.Bin) == 0 {
+		return nil
+	}
+
+	var single string
+	if err := json.Unmarshal(p.Bin, &single); err == nil {
+		// A single bin entry is named after the package (without scope)
+		return []string{name[strings.LastIndex(name, "/")+1:]}
+	}
+
+	var multi map[string]string
+	if err := json.Unmarshal(p.Bin, &multi); err == nil {
+		bins := make([]string, 0, len(multi))
+		for bin := range multi {
+			bins = append(bins, bin)
+		}
+		sort.Strings(bins)
+		return bins
+	}
+	return nil
+}
+
+// parsePackageJSON converts the devDependencies of a package.json file into npm tools.
+// Versions and binaries are taken from package-lock.json or node_modules when available.
+func parsePackageJSON(data []byte, path string, _ *Mappings) (*Result, error) {
+	var pkg npmPackage
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	var lock struct {
+		Packages map[string]npmPackage `json:"packages"`
+	}
+	if lockData, err := os.ReadFile(filepath.Join(dir, "package-lock.json")); err == nil {
+		_ = json.Unmarshal(lockData, &lock)
+	}
+
+	names := make([]string, 0, len(pkg.DevDependencies))
+	for name := range pkg.DevDependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &Result{}
+	for _, name := range names {
+		spec := pkg.DevDependencies[name]
+		if strings.Contains(spec, ":") || strings.Contains(spec, "/") {
+			result.Unmapped = append(result.Unmapped, Unmapped{Name: name, Reason: "non-registry dependency (" + spec + ")"})
+			continue
+		}
+
+		// Prefer the resolved version and bin field from the lockfile, then the installed package
+		resolved, known := lock.Packages["node_modules/"+name]
+		if !known {
+			installedData, err := os.ReadFile(filepath.Join(dir, "node_modules", name, "package.json"))
+			if err == nil && json.Unmarshal(installedData, &resolved) == nil {
+				known = true
+			}
+		}
+
+		tool := config.Tool{Type: "npm", Source: config.Source{name}, Version: spec}
+		if known {
+			bins := resolved.binaries(name)
+			if len(bins) == 0 {
+				result.Unmapped = append(result.Unmapped, Unmapped{Name: name, Reason: "library without binaries"})
+				continue
+			}
+			tool.Binaries = bins
+			if resolved.Version != "" {
+				tool.Version = resolved.Version
+			}
+		}
+		result.Tools = append(result.Tools, tool)
+	}
+	return result, nil
+}
@@ -0,0 +1,194 @@
+package importer
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// requirementPattern matches the name, optional extras and version specifier of a PEP 508 requirement.
+var requirementPattern = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?\s*([^;]*)`)
+
+// pythonRequirement is a parsed dependency from pyproject.toml.
+type pythonRequirement struct {
+	Name    string
+	Version string // Exact version if pinned with ==
+}
+
+// parsePyproject converts the dependency groups and [tool.*] sections of a
+// pyproject.toml file into uv tools. Versions are resolved from uv.lock and
+// binaries from the console scripts of packages installed in .venv.
+func parsePyproject(data []byte, path string, mappings *Mappings) (*Result, error) {
+	var doc struct {
+		DependencyGroups map[string][]any `toml:"dependency-groups"`
+		Tool             map[string]any   `toml:"tool"`
+	}
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	requirements := make(map[string]pythonRequirement)
+	addRequirement := func(spec string) {
+		if req, ok := parseRequirement(spec); ok {
+			if _, exists := requirements[req.Name]; !exists || req.Version != "" {
+				requirements[req.Name] = req
+			}
+		}
+	}
+
+	for _, group := range doc.DependencyGroups {
+		for _, item := range group {
+			// Entries can also be {include-group = "..."} tables, which are covered by iterating all groups
+			if spec, ok := item.(string); ok {
+				addRequirement(spec)
+			}
+		}
+	}
+
+	// Legacy uv dev dependencies
+	if uv, ok := doc.Tool["uv"].(map[string]any); ok {
+		if devDeps, ok := uv["dev-dependencies"].([]any); ok {
+			for _, item := range devDeps {
+				if spec, ok := item.(string); ok {
+					addRequirement(spec)
+				}
+			}
+		}
+	}
+
+	// [tool.<name>] sections of known Python CLIs indicate the tool is used by the project
+	for name := range doc.Tool {
+		if mapping, ok := mappings.Lookup(name); ok && mapping.Type == "uv" {
+			addRequirement(mapping.Source)
+		}
+	}
+
+	dir := filepath.Dir(path)
+	locked := readUvLock(filepath.Join(dir, "uv.lock"))
+	scripts := readConsoleScripts(filepath.Join(dir, ".venv"))
+
+	names := make([]string, 0, len(requirements))
+	for name := range requirements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &Result{}
+	for _, name := range names {
+		req := requirements[name]
+		tool := config.Tool{Type: "uv", Source: config.Source{req.Name}, Version: req.Version}
+		if tool.Version == "" {
+			tool.Version = locked[normalizePythonName(req.Name)]
+		}
+
+		if scripts != nil {
+			bins, installed := scripts[normalizePythonName(req.Name)]
+			if installed && len(bins) == 0 {
+				result.Unmapped = append(result.Unmapped, Unmapped{Name: req.Name, Reason: "library without console scripts"})
+				continue
+			}
+			tool.Binaries = bins
+		}
+		result.Tools = append(result.Tools, tool)
+	}
+	return result, nil
+}
+
+// parseRequirement parses a PEP 508 requirement string.
+func parseRequirement(spec string) (pythonRequirement, bool) {
+	match := requirementPattern.FindStringSubmatch(spec)
+	if match == nil {
+		return pythonRequirement{}, false
+	}
+
+	req := pythonRequirement{Name: match[1]}
+	specifier := strings.TrimSpace(match[3])
+	if strings.HasPrefix(specifier, "==") && !strings.Contains(specifier, ",") && !strings.Contains(specifier, "*") {
+		req.Version = strings.TrimSpace(strings.TrimPrefix(specifier, "=="))
+	}
+	return req, true
+}
+
+// normalizePythonName normalizes a distribution name according to PEP 503.
+func normalizePythonName(name string) string {
+	name = strings.ToLower(name)
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	}), "-")
+}
+
+// readUvLock returns the locked package versions keyed by normalized name.
+func readUvLock(path string) map[string]string {
+	var lock struct {
+		Package []struct {
+			Name    string `toml:"name"`
+			Version string `toml:"version"`
+		} `toml:"package"`
+	}
+	if _, err := toml.DecodeFile(filepath.Clean(path), &lock); err != nil {
+		return nil
+	}
+
+	versions := make(map[string]string)
+	for _, p := range lock.Package {
+		versions[normalizePythonName(p.Name)] = p.Version
+	}
+	return versions
+}
+
+// readConsoleScripts returns the console scripts of all distributions installed
+// in the virtual environment, keyed by normalized name. It returns nil if no
+// virtual environment exists.
+func readConsoleScripts(venvDir string) map[string][]string {
+	distInfos, _ := filepath.Glob(filepath.Join(venvDir, "lib", "python*", "site-packages", "*.dist-info"))
+	// Windows virtual environments have no pythonX.Y directory
+	winDistInfos, _ := filepath.Glob(filepath.Join(venvDir, "Lib", "site-packages", "*.dist-info"))
+	distInfos = append(distInfos, winDistInfos...)
+	if len(distInfos) == 0 {
+		return nil
+	}
+
+	scripts := make(map[string][]string)
+	for _, distInfo := range distInfos {
+		// The directory is named <name>-<version>.dist-info
+		base := strings.TrimSuffix(filepath.Base(distInfo), ".dist-info")
+		if idx := strings.LastIndex(base, "-"); idx != -1 {
+			base = base[:idx]
+		}
+		name := normalizePythonName(base)
+		scripts[name] = readEntryPoints(filepath.Join(distInfo, "entry_points.txt"))
+	}
+	return scripts
+}
+
+// readEntryPoints returns the names of the console scripts in an entry_points.txt file.
+func readEntryPoints(path string) []string {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return []string{}
+	}
+	defer func() { _ = f.Close() }()
+
+	bins := []string{}
+	inConsoleScripts := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inConsoleScripts = line == "[console_scripts]"
+			continue
+		}
+		if name, _, ok := strings.Cut(line, "="); ok && inConsoleScripts {
+			bins = append(bins, strings.TrimSpace(name))
+		}
+	}
+	sort.Strings(bins)
+	return bins
+}
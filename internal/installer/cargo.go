@@ -2,6 +2,7 @@ package installer
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/sebakri/box/internal/config"
@@ -23,19 +24,85 @@ func (i *CargoInstaller) Install(tool config.Tool, m *Manager, sandbox bool) ([]
 	cargoBinDir := filepath.Join(cargoDir, "bin")
 	binDir := filepath.Join(boxDir, "bin")
 
+	binaries := tool.Binaries
+	if len(binaries) == 0 {
+		binaries = []string{m.detectBinaryName(source)}
+	}
+
+	if m.Offline {
+		// cargo-binstall always resolves crates online, so the binaries fetched
+		// into .box/cache/cargo are copied instead
+		if err := os.MkdirAll(cargoBinDir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create cargo bin dir: %w", err)
+		}
+		for _, name := range binaries {
+			src, err := m.findBinary(filepath.Join(m.cachePath("cargo"), "bin"), name)
+			if err != nil {
+				return nil, fmt.Errorf("%s has not been fetched: %w", tool.DisplayName(), err)
+			}
+			m.log("Copying %s from the cache...", filepath.Base(src))
+			if err := copyFile(src, filepath.Join(cargoBinDir, filepath.Base(src)), 0700); err != nil {
+				return nil, fmt.Errorf("failed to copy %s from the cache: %w", name, err)
+			}
+		}
+		return m.linkBinaries(tool, cargoBinDir, binDir, binaries)
+	}
+
+	regArgs, env, err := m.cargoRegistry()
+	if err != nil {
+		return nil, err
+	}
+
 	// cargo-binstall --root .box/cargo <args> <package>
 	args := []string{"--root", cargoDir, "-y"}
+	args = append(args, regArgs...)
 	args = append(args, tool.Args...)
 	args = append(args, source)
 
-	if err := m.runCommand("cargo-binstall", args, nil, "", sandbox); err != nil {
+	if err := m.runCommand("cargo-binstall", args, env, "", sandbox); err != nil {
 		return nil, err
 	}
 
-	binaries := tool.Binaries
-	if len(binaries) == 0 {
-		binaries = []string{m.detectBinaryName(source)}
+	return m.linkBinaries(tool, cargoBinDir, binDir, binaries)
+}
+
+// Fetch downloads the prebuilt binaries of a crate into .box/cache/cargo.
+func (i *CargoInstaller) Fetch(tool config.Tool, m *Manager, sandbox bool) error {
+	source := tool.Source.String()
+	if tool.Version != "" {
+		source = fmt.Sprintf("%s@%s", source, tool.Version)
+	}
+	m.log("Fetching %s (cargo)...", tool.DisplayName())
+
+	regArgs, env, err := m.cargoRegistry()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"--root", m.cachePath("cargo"), "-y"}
+	args = append(args, regArgs...)
+	args = append(args, tool.Args...)
+	args = append(args, source)
+
+	return m.runCommand("cargo-binstall", args, env, "", sandbox)
+}
+
+// cargoRegistry returns the arguments and environment that point
+// cargo-binstall to the configured registry index. The token is passed in
+// CARGO_REGISTRY_TOKEN rather than on the command line.
+func (m *Manager) cargoRegistry() ([]string, []string, error) {
+	reg, ok := m.Registries["cargo"]
+	if !ok || reg.URL == "" {
+		return nil, nil, nil
+	}
+	token, err := reg.Token()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cargo registry: %w", err)
 	}
 
-	return m.linkBinaries(cargoBinDir, binDir, binaries)
+	var env []string
+	if token != "" {
+		env = append(env, "CARGO_REGISTRY_TOKEN="+token)
+	}
+	return []string{"--index", reg.URL}, env, nil
 }
@@ -0,0 +1,61 @@
+package installer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// CIProviders maps supported CI providers to the file generated for them.
+var CIProviders = map[string]string{
+	"github": filepath.Join(".github", "workflows", "box.yml"),
+	"gitlab": filepath.Join(".gitlab", "ci", "box.yml"),
+}
+
+// ciData is the data passed to the CI templates.
+type ciData struct {
+	Path       string
+	ConfigFile string
+}
+
+// GenerateCI creates a CI pipeline definition for the given provider that installs
+// and caches the project's tools. It returns the path of the generated file.
+func (m *Manager) GenerateCI(provider string) (string, error) {
+	relPath, ok := CIProviders[provider]
+	if !ok {
+		return "", fmt.Errorf("unsupported CI provider: %s", provider)
+	}
+
+	text, err := templatesFS.ReadFile("templates/ci-" + provider + ".yml.tmpl")
+	if err != nil {
+		return "", fmt.Errorf("failed to read CI template: %w", err)
+	}
+
+	tmpl, err := template.New(provider).Parse(string(text))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse CI template: %w", err)
+	}
+
+	data := ciData{
+		Path:       filepath.ToSlash(relPath),
+		ConfigFile: m.configFileName(),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render CI template: %w", err)
+	}
+
+	path := filepath.Join(m.RootDir, relPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("failed to create directory for %s: %w", relPath, err)
+	}
+
+	m.log("Generating %s...", relPath)
+	if err := os.WriteFile(filepath.Clean(path), buf.Bytes(), 0600); err != nil {
+		return "", err
+	}
+	return relPath, nil
+}
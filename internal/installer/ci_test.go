@@ -0,0 +1,54 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateCI(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	m := New(tmpDir, "", nil, nil)
+	m.Output = nil
+
+	tests := []struct {
+		provider string
+		contains []string
+	}{
+		{"github", []string{"box cache-key", "actions/cache@v4", "${{ steps.box-cache-key.outputs.key }}", "box install -y", "/.box/bin\" >> \"$GITHUB_PATH\""}},
+		{"gitlab", []string{"box cache-key", "dotenv: box-cache-key.env", "key: $BOX_CACHE_KEY", "box install -y", "$CI_PROJECT_DIR/.box/bin:$PATH"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			relPath, err := m.GenerateCI(tt.provider)
+			if err != nil {
+				t.Fatalf("GenerateCI failed: %v", err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(tmpDir, relPath))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var parsed map[string]any
+			if err := yaml.Unmarshal(data, &parsed); err != nil {
+				t.Fatalf("generated file is not valid YAML: %v\n%s", err, data)
+			}
+
+			for _, want := range tt.contains {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("expected %s to contain %q:\n%s", relPath, want, data)
+				}
+			}
+		})
+	}
+
+	if _, err := m.GenerateCI("jenkins"); err == nil {
+		t.Error("expected error for unsupported provider")
+	}
+}
@@ -0,0 +1,457 @@
+package installer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	devcontainerWorkspace = "${containerWorkspaceFolder}"
+	devcontainerBoxDir    = devcontainerWorkspace + "/.box"
+	devcontainerBinDir    = devcontainerBoxDir + "/bin"
+	devcontainerInstall   = "box install -y"
+)
+
+// GenerateDevcontainer creates or updates .devcontainer/devcontainer.json for the project.
+// Existing files are merged so that user customizations are preserved; only the
+// settings box is responsible for are added or refreshed.
+func (m *Manager) GenerateDevcontainer() error {
+	devcontainerDir := filepath.Join(m.RootDir, ".devcontainer")
+	devcontainerPath := filepath.Join(devcontainerDir, "devcontainer.json")
+
+	if err := os.MkdirAll(devcontainerDir, 0700); err != nil {
+		return fmt.Errorf("failed to create .devcontainer dir: %w", err)
+	}
+
+	existing, original := map[string]any{}, map[string]any{}
+	data, err := os.ReadFile(filepath.Clean(devcontainerPath))
+	comments := false
+	if err == nil {
+		var stripped []byte
+		stripped, comments = stripJSONComments(data)
+		if err := json.Unmarshal(stripped, &existing); err != nil {
+			return fmt.Errorf("failed to parse existing %s: %w", devcontainerPath, err)
+		}
+		_ = json.Unmarshal(stripped, &original)
+		m.log("Merging into existing %s...", devcontainerPath)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	merged, err := m.mergeDevcontainer(existing)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", devcontainerPath, err)
+	}
+
+	if comments {
+		// Rewriting the whole file would drop the comments, so only the
+		// settings box changes are replaced
+		updated, changed, err := updateJSONC(data, original, merged)
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", devcontainerPath, err)
+		}
+		if len(changed) > 0 {
+			m.log("Updating %s in %s, keeping comments elsewhere...", strings.Join(changed, ", "), devcontainerPath)
+		}
+		return os.WriteFile(filepath.Clean(devcontainerPath), updated, 0600)
+	}
+
+	data, err = marshalJSON(merged, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	m.log("Generating %s...", devcontainerPath)
+	return os.WriteFile(filepath.Clean(devcontainerPath), append(data, '\n'), 0600)
+}
+
+func (m *Manager) mergeDevcontainer(dc map[string]any) (map[string]any, error) {
+	setDefault(dc, "name", filepath.Base(m.RootDir))
+
+	// Only reference the Dockerfile if the user has not chosen an image or compose setup
+	_, hasImage := dc["image"]
+	_, hasCompose := dc["dockerComposeFile"]
+	if !hasImage && !hasCompose {
+		setDefault(dc, "build", map[string]any{
+			"dockerfile": "../Dockerfile",
+			"context":    "..",
+		})
+	}
+
+	containerEnv := objectField(dc, "containerEnv")
+	for k, v := range m.Env {
+		containerEnv[k] = v
+	}
+	containerEnv["BOX_DIR"] = devcontainerBoxDir
+	containerEnv["BOX_BIN_DIR"] = devcontainerBinDir
+
+	remoteEnv := objectField(dc, "remoteEnv")
+	remoteEnv["PATH"] = devcontainerBinDir + ":${containerEnv:PATH}"
+
+	// Keep any existing post create command and append the box install step
+	switch cmd := dc["postCreateCommand"].(type) {
+	case nil:
+		dc["postCreateCommand"] = devcontainerInstall
+	case string:
+		if !strings.Contains(cmd, "box install") {
+			dc["postCreateCommand"] = cmd + " && " + devcontainerInstall
+		}
+	case []any:
+		// The array form runs without a shell, so it becomes a shell command
+		// that runs the install afterwards
+		args := make([]string, 0, len(cmd))
+		for _, arg := range cmd {
+			s, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("postCreateCommand contains the non-string argument %v", arg)
+			}
+			args = append(args, shellQuote(s))
+		}
+		if joined := strings.Join(args, " "); !strings.Contains(joined, "box install") {
+			dc["postCreateCommand"] = joined + " && " + devcontainerInstall
+		}
+	case map[string]any:
+		// Named commands run in parallel, so box install is added as another one
+		installs := false
+		for _, c := range cmd {
+			installs = installs || runsBoxInstall(c)
+		}
+		if !installs {
+			cmd["box"] = devcontainerInstall
+		}
+	default:
+		return nil, fmt.Errorf("unsupported postCreateCommand %v", cmd)
+	}
+
+	// Persist .box in a named volume so caches and installed tools survive rebuilds
+	mount := fmt.Sprintf("source=box-%s,target=%s,type=volume", filepath.Base(m.RootDir), devcontainerBoxDir)
+	mounts, _ := dc["mounts"].([]any)
+	hasBoxMount := false
+	for _, existing := range mounts {
+		if s, ok := existing.(string); ok && strings.Contains(s, "target="+devcontainerBoxDir) {
+			hasBoxMount = true
+			break
+		}
+	}
+	if !hasBoxMount {
+		dc["mounts"] = append(mounts, mount)
+	}
+
+	return dc, nil
+}
+
+// runsBoxInstall reports whether a command in the string or array form of
+// postCreateCommand runs box install.
+func runsBoxInstall(cmd any) bool {
+	switch cmd := cmd.(type) {
+	case string:
+		return strings.Contains(cmd, "box install")
+	case []any:
+		return len(cmd) >= 2 && cmd[0] == "box" && cmd[1] == "install"
+	}
+	return false
+}
+
+// shellQuote quotes s for sh unless it only contains safe characters.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:@%+,") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func setDefault(obj map[string]any, key string, value any) {
+	if _, ok := obj[key]; !ok {
+		obj[key] = value
+	}
+}
+
+func objectField(obj map[string]any, key string) map[string]any {
+	if existing, ok := obj[key].(map[string]any); ok {
+		return existing
+	}
+	field := map[string]any{}
+	obj[key] = field
+	return field
+}
+
+// stripJSONComments removes // and /* */ comments as well as trailing commas,
+// which are allowed in devcontainer.json (JSONC) but not in encoding/json. It
+// also reports whether there were comments.
+func stripJSONComments(data []byte) ([]byte, bool) {
+	var out []byte
+	comments := false
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			comments = true
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			comments = true
+			i += 2
+			for i+1 < len(data) && (data[i] != '*' || data[i+1] != '/') {
+				i++
+			}
+			i++
+		case c == '}' || c == ']':
+			// Drop a trailing comma before the closing bracket
+			j := len(out) - 1
+			for j >= 0 && (out[j] == ' ' || out[j] == '\t' || out[j] == '\n' || out[j] == '\r') {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out, comments
+}
+
+// updateJSONC applies the top-level keys that differ between before and after
+// to the JSONC document data, which before was parsed from. Changed values
+// are replaced and new keys are added at the end of the object, so comments
+// outside the changed values are kept. It returns the document and the names
+// of the changed keys.
+func updateJSONC(data []byte, before, after map[string]any) ([]byte, []string, error) {
+	members, open, err := scanJSONCObject(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	after = normalizeJSON(after)
+	indent := jsoncIndent(data, open)
+	var changed, added []string
+	for key, value := range after {
+		if old, ok := before[key]; !ok || !reflect.DeepEqual(old, value) {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+	for _, key := range changed {
+		value, err := marshalJSON(after[key], indent, indent)
+		if err != nil {
+			return nil, nil, err
+		}
+		if member, ok := members[key]; ok {
+			edits = append(edits, edit{member.start, member.end, string(value)})
+			continue
+		}
+		name, _ := marshalJSON(key, "", "")
+		added = append(added, fmt.Sprintf("\n%s%s: %s", indent, name, value))
+	}
+	if len(added) > 0 {
+		// New keys follow the last member, or open the object if it is empty
+		last := -1
+		for _, member := range members {
+			last = max(last, member.end)
+		}
+		if last == -1 {
+			edits = append(edits, edit{open + 1, open + 1, strings.Join(added, ",") + "\n"})
+		} else {
+			edits = append(edits, edit{last, last, "," + strings.Join(added, ",")})
+		}
+	}
+
+	// Later edits first, so the offsets of earlier ones stay valid
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	out := slices.Clone(data)
+	for _, e := range edits {
+		out = slices.Concat(out[:e.start], []byte(e.text), out[e.end:])
+	}
+
+	// The result must still parse to the merged settings
+	var check map[string]any
+	stripped, _ := stripJSONComments(out)
+	if err := json.Unmarshal(stripped, &check); err != nil || !reflect.DeepEqual(after, check) {
+		return nil, nil, fmt.Errorf("failed to keep the comments while updating the file (%v)", err)
+	}
+	return out, changed, nil
+}
+
+// marshalJSON is json.MarshalIndent without escaping &, < and >, which are
+// common in commands.
+func marshalJSON(v any, prefix, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent(prefix, indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// jsoncIndent returns the indentation of the first line after the opening
+// brace at open, or two spaces.
+func jsoncIndent(data []byte, open int) string {
+	line := data[open+1:]
+	if i := bytes.IndexByte(line, '\n'); i != -1 {
+		line = line[i+1:]
+		if indent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]; len(indent) > 0 {
+			return string(indent)
+		}
+	}
+	return "  "
+}
+
+// normalizeJSON returns v as encoding/json decodes it, e.g. with map[string]string as map[string]any.
+func normalizeJSON(v map[string]any) map[string]any {
+	data, _ := json.Marshal(v)
+	var out map[string]any
+	_ = json.Unmarshal(data, &out)
+	return out
+}
+
+// jsoncSpan is the position of a member value in a JSONC document.
+type jsoncSpan struct {
+	start, end int
+}
+
+// scanJSONCObject returns the value spans of the members of the top-level
+// object of a JSONC document and the position of its opening brace.
+func scanJSONCObject(data []byte) (map[string]jsoncSpan, int, error) {
+	open := skipJSONC(data, 0)
+	if open >= len(data) || data[open] != '{' {
+		return nil, 0, fmt.Errorf("expected a JSON object")
+	}
+
+	members := make(map[string]jsoncSpan)
+	for i := open + 1; ; {
+		i = skipJSONC(data, i)
+		switch {
+		case i >= len(data):
+			return nil, 0, fmt.Errorf("unexpected end of JSON object")
+		case data[i] == '}':
+			return members, open, nil
+		case data[i] == ',':
+			i++
+			continue
+		case data[i] != '"':
+			return nil, 0, fmt.Errorf("unexpected %q at offset %d", data[i], i)
+		}
+
+		end, err := scanJSONCValue(data, i)
+		if err != nil {
+			return nil, 0, err
+		}
+		var key string
+		if err := json.Unmarshal(data[i:end], &key); err != nil {
+			return nil, 0, err
+		}
+		i = skipJSONC(data, end)
+		if i >= len(data) || data[i] != ':' {
+			return nil, 0, fmt.Errorf("expected ':' after %q", key)
+		}
+		start := skipJSONC(data, i+1)
+		if i, err = scanJSONCValue(data, start); err != nil {
+			return nil, 0, err
+		}
+		members[key] = jsoncSpan{start, i}
+	}
+}
+
+// skipJSONC returns the position of the next token at or after i, skipping
+// whitespace and comments.
+func skipJSONC(data []byte, i int) int {
+	for i < len(data) {
+		switch {
+		case data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r':
+			i++
+		case bytes.HasPrefix(data[i:], []byte("//")):
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case bytes.HasPrefix(data[i:], []byte("/*")):
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end == -1 {
+				return len(data)
+			}
+			i += end + 4
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// scanJSONCValue returns the end of the value starting at i.
+func scanJSONCValue(data []byte, i int) (int, error) {
+	depth := 0
+	for start := i; i < len(data); {
+		switch c := data[i]; {
+		case c == '"':
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(data) {
+				return 0, fmt.Errorf("unterminated string at offset %d", start)
+			}
+			i++
+		case c == '{' || c == '[':
+			depth++
+			i++
+		case c == '}' || c == ']':
+			if depth == 0 {
+				return i, nil
+			}
+			depth--
+			i++
+		case depth > 0 && (c == '/' || c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			if next := skipJSONC(data, i); next != i {
+				i = next
+			} else {
+				i++
+			}
+		case depth == 0 && (c == ',' || c == '/' || c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			return i, nil
+		default:
+			i++
+		}
+		if depth == 0 && i > start && (data[i-1] == '"' || data[i-1] == '}' || data[i-1] == ']') {
+			return i, nil
+		}
+	}
+	if depth > 0 {
+		return 0, fmt.Errorf("unexpected end of JSON value at offset %d", i)
+	}
+	return i, nil
+}
@@ -0,0 +1,194 @@
+package installer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateDevcontainerMergesExisting(t *testing.T) {
+	tmpDir := t.TempDir()
+	dcDir := filepath.Join(tmpDir, ".devcontainer")
+	if err := os.MkdirAll(dcDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	existing := `{
+  "name": "my-project",
+  "image": "mcr.microsoft.com/devcontainers/base:debian",
+  "containerEnv": {"EDITOR": "vim"},
+  "postCreateCommand": "make setup",
+  "customizations": {"vscode": {"extensions": ["golang.go"]}},
+}`
+	if err := os.WriteFile(filepath.Join(dcDir, "devcontainer.json"), []byte(existing), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(tmpDir, "", map[string]string{"APP_DEBUG": "true"}, nil)
+	m.Output = nil
+	if err := m.GenerateDevcontainer(); err != nil {
+		t.Fatalf("GenerateDevcontainer failed: %v", err)
+	}
+	// Running twice must not duplicate the install step or the volume mount
+	if err := m.GenerateDevcontainer(); err != nil {
+		t.Fatalf("GenerateDevcontainer failed on second run: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dcDir, "devcontainer.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dc map[string]any
+	if err := json.Unmarshal(data, &dc); err != nil {
+		t.Fatalf("generated devcontainer.json is not valid JSON: %v", err)
+	}
+
+	if dc["name"] != "my-project" {
+		t.Errorf("expected name to be preserved, got %v", dc["name"])
+	}
+	if _, ok := dc["build"]; ok {
+		t.Error("expected no build section when an image is configured")
+	}
+	if _, ok := dc["customizations"]; !ok {
+		t.Error("expected customizations to be preserved")
+	}
+	if dc["postCreateCommand"] != "make setup && box install -y" {
+		t.Errorf("unexpected postCreateCommand: %v", dc["postCreateCommand"])
+	}
+
+	env, _ := dc["containerEnv"].(map[string]any)
+	if env["EDITOR"] != "vim" || env["APP_DEBUG"] != "true" {
+		t.Errorf("unexpected containerEnv: %v", env)
+	}
+
+	remoteEnv, _ := dc["remoteEnv"].(map[string]any)
+	if remoteEnv["PATH"] != "${containerWorkspaceFolder}/.box/bin:${containerEnv:PATH}" {
+		t.Errorf("unexpected remoteEnv.PATH: %v", remoteEnv["PATH"])
+	}
+
+	mounts, _ := dc["mounts"].([]any)
+	if len(mounts) != 1 {
+		t.Errorf("expected exactly one mount, got %v", mounts)
+	}
+}
+
+func TestStripJSONComments(t *testing.T) {
+	input := `{
+  // comment
+  "url": "http://example.com", /* block */
+  "list": [1, 2,],
+}`
+	stripped, comments := stripJSONComments([]byte(input))
+	var out map[string]any
+	if err := json.Unmarshal(stripped, &out); err != nil {
+		t.Fatalf("failed to parse stripped JSON: %v", err)
+	}
+	if out["url"] != "http://example.com" {
+		t.Errorf("string containing // was modified: %v", out["url"])
+	}
+	if !comments {
+		t.Error("expected the comments to be reported")
+	}
+	if _, comments := stripJSONComments([]byte(`{"url": "http://example.com", "list": [1,],}`)); comments {
+		t.Error("expected no comments to be reported for // in strings and trailing commas")
+	}
+}
+
+func TestGenerateDevcontainerKeepsComments(t *testing.T) {
+	tests := map[string]string{
+		"vscode": `// For format details, see https://aka.ms/devcontainer.json.
+{
+	"name": "Go",
+	// Or use a Dockerfile or Docker Compose file.
+	"image": "mcr.microsoft.com/devcontainers/go:1-1.22-bookworm",
+	/* Features to add, e.g. {"a": "}"} */
+	// "features": {},
+	"postCreateCommand": "go version", // runs once
+	"customizations": {
+		// Configure properties specific to VS Code.
+		"vscode": {"extensions": ["golang.go"]}
+	},
+}
+`,
+		"empty": "{\n  // nothing yet\n}\n",
+	}
+	for name, existing := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			path := filepath.Join(tmpDir, ".devcontainer", "devcontainer.json")
+			writeTestFile(t, path, existing)
+
+			m := New(tmpDir, "", map[string]string{"APP_DEBUG": "true"}, nil)
+			m.Output = nil
+			if err := m.GenerateDevcontainer(); err != nil {
+				t.Fatalf("GenerateDevcontainer failed: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, line := range strings.Split(existing, "\n") {
+				if comment := strings.TrimSpace(line); strings.HasPrefix(comment, "//") || strings.HasPrefix(comment, "/*") {
+					if !strings.Contains(string(data), comment) {
+						t.Errorf("expected comment %q to be kept:\n%s", comment, data)
+					}
+				}
+			}
+
+			stripped, _ := stripJSONComments(data)
+			var dc map[string]any
+			if err := json.Unmarshal(stripped, &dc); err != nil {
+				t.Fatalf("updated devcontainer.json is not valid JSONC: %v\n%s", err, data)
+			}
+			if cmd, _ := dc["postCreateCommand"].(string); !strings.HasSuffix(cmd, "box install -y") {
+				t.Errorf("unexpected postCreateCommand: %v", dc["postCreateCommand"])
+			}
+			if env, _ := dc["containerEnv"].(map[string]any); env["APP_DEBUG"] != "true" {
+				t.Errorf("unexpected containerEnv: %v", dc["containerEnv"])
+			}
+
+			// Running again changes nothing
+			if err := m.GenerateDevcontainer(); err != nil {
+				t.Fatalf("GenerateDevcontainer failed on second run: %v", err)
+			}
+			if again, _ := os.ReadFile(path); string(again) != string(data) {
+				t.Errorf("expected the second run to keep the file, got:\n%s", again)
+			}
+		})
+	}
+}
+
+func TestMergeDevcontainerPostCreateCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing any
+		want     any
+	}{
+		{"string", "make setup", "make setup && box install -y"},
+		{"array", []any{"npm", "install", "--prefix", "my dir"}, "npm install --prefix 'my dir' && box install -y"},
+		{"array with box", []any{"box", "install", "-y"}, []any{"box", "install", "-y"}},
+		{"object", map[string]any{"deps": "make setup"}, map[string]any{"deps": "make setup", "box": "box install -y"}},
+		{"object with box", map[string]any{"tools": []any{"box", "install"}}, map[string]any{"tools": []any{"box", "install"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(t.TempDir(), "", nil, nil)
+			dc, err := m.mergeDevcontainer(map[string]any{"postCreateCommand": tt.existing})
+			if err != nil {
+				t.Fatalf("mergeDevcontainer failed: %v", err)
+			}
+			if !reflect.DeepEqual(dc["postCreateCommand"], tt.want) {
+				t.Errorf("expected postCreateCommand %v, got %v", tt.want, dc["postCreateCommand"])
+			}
+		})
+	}
+
+	m := New(t.TempDir(), "", nil, nil)
+	if _, err := m.mergeDevcontainer(map[string]any{"postCreateCommand": 42.0}); err == nil {
+		t.Error("expected an error for an unsupported postCreateCommand")
+	}
+}
@@ -0,0 +1,220 @@
+package installer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// DefaultBaseImage is the base image used for generated Dockerfiles.
+const DefaultBaseImage = "debian:bookworm-slim"
+
+// DefaultGoVersion is the Go toolchain installed in generated Dockerfiles.
+const DefaultGoVersion = "1.24.2"
+
+//go:embed templates
+var templatesFS embed.FS
+
+// DockerfileOptions controls how the Dockerfile is generated.
+type DockerfileOptions struct {
+	BaseImage  string // Base image for all stages (defaults to DefaultBaseImage)
+	Platform   string // Optional target platform, e.g. "linux/arm64"
+	MultiStage bool   // Install tools in a builder stage and copy only .box to the final image
+	Template   string // Optional path to a custom template file
+	BoxVersion string // Release of box to install in the image; other versions copy a box binary from the build context
+}
+
+// dockerfileData is the data passed to the Dockerfile template.
+type dockerfileData struct {
+	BaseImage       string
+	Platform        string
+	MultiStage      bool
+	BoxVersion      string
+	ConfigFile      string
+	ContextFiles    []string
+	GoVersion       string
+	GoArch          string
+	Go              bool
+	Node            bool
+	Ruby            bool
+	Cargo           bool
+	Uv              bool
+	Packages        []string
+	RuntimePackages []string
+	Env             []envVar
+}
+
+type envVar struct {
+	Key   string
+	Value string
+}
+
+// GenerateDockerfile creates a Dockerfile for the project.
+func (m *Manager) GenerateDockerfile(opts DockerfileOptions) error {
+	dockerfilePath := filepath.Join(m.RootDir, "Dockerfile")
+
+	content, err := m.RenderDockerfile(opts)
+	if err != nil {
+		return err
+	}
+
+	m.log("Generating Dockerfile...")
+	return os.WriteFile(dockerfilePath, content, 0600)
+}
+
+// RenderDockerfile renders the Dockerfile template for the configured tools.
+func (m *Manager) RenderDockerfile(opts DockerfileOptions) ([]byte, error) {
+	var (
+		text []byte
+		err  error
+	)
+	if opts.Template != "" {
+		text, err = os.ReadFile(filepath.Clean(opts.Template))
+	} else {
+		text, err = templatesFS.ReadFile("templates/Dockerfile.tmpl")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Dockerfile template: %w", err)
+	}
+
+	tmpl, err := template.New("Dockerfile").Funcs(template.FuncMap{
+		"join":        strings.Join,
+		"dockerQuote": dockerQuote,
+	}).Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Dockerfile template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, m.dockerfileData(opts)); err != nil {
+		return nil, fmt.Errorf("failed to render Dockerfile template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (m *Manager) dockerfileData(opts DockerfileOptions) dockerfileData {
+	data := dockerfileData{
+		BaseImage:    opts.BaseImage,
+		Platform:     opts.Platform,
+		MultiStage:   opts.MultiStage,
+		ConfigFile:   m.configFileName(),
+		ContextFiles: m.contextFiles(),
+		GoVersion:    DefaultGoVersion,
+	}
+	if data.BaseImage == "" {
+		data.BaseImage = DefaultBaseImage
+	}
+	// Development builds have no release to install
+	if exactVersion.MatchString(opts.BoxVersion) {
+		data.BoxVersion = opts.BoxVersion
+	}
+	if opts.Platform != "" {
+		data.GoArch = goTarballArch(opts.Platform)
+	}
+
+	if m.GlobalConfig != nil {
+		for _, tool := range m.GlobalConfig.Tools {
+			switch tool.Type {
+			case "go":
+				data.Go = true
+			case "npm":
+				data.Node = true
+			case "gem":
+				data.Ruby = true
+			case "cargo":
+				data.Cargo = true
+			case "uv":
+				data.Uv = true
+			}
+		}
+	}
+
+	data.Packages = []string{"ca-certificates", "curl", "git"}
+	data.RuntimePackages = []string{"ca-certificates", "git"}
+	if data.Go || data.Ruby {
+		data.Packages = append(data.Packages, "build-essential")
+	}
+	if data.Node {
+		data.Packages = append(data.Packages, "nodejs", "npm")
+		data.RuntimePackages = append(data.RuntimePackages, "nodejs")
+	}
+	if data.Ruby {
+		data.Packages = append(data.Packages, "ruby-full")
+		data.RuntimePackages = append(data.RuntimePackages, "ruby")
+	}
+	sort.Strings(data.Packages)
+	sort.Strings(data.RuntimePackages)
+
+	keys := make([]string, 0, len(m.Env))
+	for k := range m.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		data.Env = append(data.Env, envVar{Key: k, Value: m.Env[k]})
+	}
+
+	return data
+}
+
+// contextFiles returns the files box install reads in the image, the
+// config.Inputs of the configuration, relative to the project root.
+func (m *Manager) contextFiles() []string {
+	path := m.ConfigFile
+	if path == "" {
+		path = filepath.Join(m.RootDir, m.configFileName())
+	}
+	inputs, err := config.Inputs(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			m.warn("Failed to list the configuration files for the image: %v", err)
+		}
+		inputs = []string{path}
+	}
+
+	root, _ := filepath.Abs(m.RootDir)
+	files := make([]string, 0, len(inputs))
+	for _, file := range inputs {
+		abs, _ := filepath.Abs(file)
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			m.warn("%s is outside the project and is not copied into the image", file)
+			continue
+		}
+		files = append(files, filepath.ToSlash(rel))
+	}
+	return files
+}
+
+// goTarballArch maps a Docker platform (e.g. "linux/arm/v7") to the
+// architecture suffix used by the official Go release tarballs.
+func goTarballArch(platform string) string {
+	parts := strings.Split(platform, "/")
+	arch := parts[len(parts)-1]
+	if len(parts) >= 2 {
+		arch = parts[1]
+	}
+	switch arch {
+	case "arm":
+		return "armv6l"
+	case "x86_64":
+		return "amd64"
+	case "aarch64":
+		return "arm64"
+	}
+	return arch
+}
+
+// dockerQuote quotes a value for use in a Dockerfile ENV instruction.
+// Variable references are escaped so the value is taken literally, as box does.
+func dockerQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,209 @@
+package installer
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+func TestRenderDockerfile(t *testing.T) {
+	cfg := &config.Config{
+		Tools: []config.Tool{
+			{Type: "go", Source: config.Source{"github.com/go-task/task/v3/cmd/task"}},
+			{Type: "npm", Source: config.Source{"cowsay"}},
+		},
+	}
+	m := New(t.TempDir(), "", map[string]string{"APP_DEBUG": "true", "GREETING": `say "$HOME"`}, cfg)
+
+	out, err := m.RenderDockerfile(DockerfileOptions{})
+	if err != nil {
+		t.Fatalf("RenderDockerfile failed: %v", err)
+	}
+	content := string(out)
+
+	for _, want := range []string{
+		"FROM debian:bookworm-slim",
+		"ARG TARGETARCH",
+		`case "$TARGETARCH" in arm) GO_ARCH=armv6l ;;`,
+		"go${GO_VERSION}.linux-${GO_ARCH}.tar.gz",
+		"nodejs npm",
+		`ENV APP_DEBUG="true"`,
+		`ENV GREETING="say \"\$HOME\""`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected Dockerfile to contain %q:\n%s", want, content)
+		}
+	}
+	for _, unwanted := range []string{"cargo-binstall", "astral.sh/uv", "ruby-full", "AS builder"} {
+		if strings.Contains(content, unwanted) {
+			t.Errorf("expected Dockerfile not to contain %q:\n%s", unwanted, content)
+		}
+	}
+}
+
+func TestRenderDockerfileMultiStage(t *testing.T) {
+	cfg := &config.Config{
+		Tools: []config.Tool{
+			{Type: "go", Source: config.Source{"golang.org/x/tools/gopls"}},
+			{Type: "uv", Source: config.Source{"ruff"}},
+		},
+	}
+	m := New(t.TempDir(), "", nil, cfg)
+
+	out, err := m.RenderDockerfile(DockerfileOptions{
+		BaseImage:  "ubuntu:24.04",
+		Platform:   "linux/arm64",
+		MultiStage: true,
+	})
+	if err != nil {
+		t.Fatalf("RenderDockerfile failed: %v", err)
+	}
+	content := string(out)
+
+	for _, want := range []string{
+		"FROM --platform=linux/arm64 ubuntu:24.04 AS builder",
+		".linux-arm64.tar.gz",
+		"UV_PYTHON_INSTALL_DIR",
+		"COPY --from=builder --chown=box:box /home/box/.box /home/box/.box",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected Dockerfile to contain %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "ARG TARGETARCH") {
+		t.Errorf("expected no TARGETARCH when a platform is given:\n%s", content)
+	}
+}
+
+func TestRenderDockerfileBoxVersion(t *testing.T) {
+	m := New(t.TempDir(), "", nil, &config.Config{})
+	tests := map[string]string{
+		"v1.2.3": "sebakri/box/v1.2.3/scripts/install.sh | BOX_VERSION=v1.2.3 ",
+		"dev":    "COPY --link --chmod=755 box /usr/local/bin/box",
+		"":       "COPY --link --chmod=755 box /usr/local/bin/box",
+	}
+	for version, want := range tests {
+		out, err := m.RenderDockerfile(DockerfileOptions{BoxVersion: version})
+		if err != nil {
+			t.Fatalf("RenderDockerfile failed: %v", err)
+		}
+		if !strings.Contains(string(out), want) {
+			t.Errorf("version %q: expected Dockerfile to contain %q:\n%s", version, want, out)
+		}
+		if strings.Contains(string(out), "/main/") {
+			t.Errorf("version %q: expected no unpinned install of box:\n%s", version, out)
+		}
+	}
+}
+
+func TestRenderDockerfileContextFiles(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"box.yml":                "extends: [ci/base.yml]\ngo_mod_tools: true\nworkspace:\n  members: [services/*]\n",
+		"ci/base.yml":            "env:\n  A: b\n",
+		"go.mod":                 "module example.com/app\n",
+		"go.sum":                 "",
+		"services/api/.box.yml":  "extends: [tools.yml]\n",
+		"services/api/tools.yml": "tools: []\n",
+		config.LocalFile:         "env:\n  C: d\n",
+	}
+	for name, content := range files {
+		writeTestFile(t, filepath.Join(root, name), content)
+	}
+	cfg, err := config.Load(filepath.Join(root, "box.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New(root, "", nil, cfg)
+	m.ConfigFile = filepath.Join(root, "box.yml")
+
+	out, err := m.RenderDockerfile(DockerfileOptions{MultiStage: true})
+	if err != nil {
+		t.Fatalf("RenderDockerfile failed: %v", err)
+	}
+	for _, file := range []string{"box.yml", "ci/base.yml", "go.mod", "go.sum", "services/api/.box.yml", "services/api/tools.yml"} {
+		for _, want := range []string{
+			"COPY --chown=box:box " + file + " " + file + "\n",
+			"COPY --from=builder --chown=box:box /home/box/" + file + " /home/box/" + file + "\n",
+		} {
+			if !strings.Contains(string(out), want) {
+				t.Errorf("expected Dockerfile to contain %q:\n%s", want, out)
+			}
+		}
+	}
+	if strings.Contains(string(out), config.LocalFile) {
+		t.Errorf("expected the local overlay not to be copied:\n%s", out)
+	}
+}
+
+func TestRenderDockerfileCustomTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmplPath := filepath.Join(tmpDir, "Dockerfile.tmpl")
+	if err := os.WriteFile(tmplPath, []byte("FROM {{ .BaseImage }}\n{{ if .Go }}# go{{ end }}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Tools: []config.Tool{{Type: "go", Source: config.Source{"example.com/tool"}}}}
+	m := New(tmpDir, "", nil, cfg)
+
+	out, err := m.RenderDockerfile(DockerfileOptions{Template: tmplPath, BaseImage: "alpine"})
+	if err != nil {
+		t.Fatalf("RenderDockerfile failed: %v", err)
+	}
+	if string(out) != "FROM alpine\n# go\n" {
+		t.Errorf("unexpected output: %q", string(out))
+	}
+}
+
+func TestGoTarballArch(t *testing.T) {
+	tests := map[string]string{
+		"linux/amd64":  "amd64",
+		"linux/arm64":  "arm64",
+		"linux/arm/v7": "armv6l",
+		"arm64":        "arm64",
+	}
+	for platform, expected := range tests {
+		if got := goTarballArch(platform); got != expected {
+			t.Errorf("goTarballArch(%q) = %q, want %q", platform, got, expected)
+		}
+	}
+}
+
+func TestDockerfileGoArchMapping(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the mapping runs with sh")
+	}
+
+	cfg := &config.Config{Tools: []config.Tool{{Type: "go", Source: config.Source{"github.com/go-task/task/v3/cmd/task"}}}}
+	out, err := New(t.TempDir(), "", nil, cfg).RenderDockerfile(DockerfileOptions{})
+	if err != nil {
+		t.Fatalf("RenderDockerfile failed: %v", err)
+	}
+
+	var mapping string
+	for _, line := range strings.Split(string(out), "\n") {
+		if rest, ok := strings.CutPrefix(line, "RUN case "); ok {
+			mapping, _, _ = strings.Cut("case "+rest, " && \\")
+		}
+	}
+	if mapping == "" {
+		t.Fatalf("expected a TARGETARCH mapping in the Dockerfile:\n%s", out)
+	}
+
+	for targetArch, want := range map[string]string{"amd64": "amd64", "arm64": "arm64", "arm": "armv6l"} {
+		cmd := exec.Command("sh", "-c", mapping+` && echo "$GO_ARCH"`)
+		cmd.Env = append(os.Environ(), "TARGETARCH="+targetArch)
+		got, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("mapping failed: %v", err)
+		}
+		if strings.TrimSpace(string(got)) != want {
+			t.Errorf("TARGETARCH=%s: expected Go tarball arch %s, got %q", targetArch, want, got)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// Fetcher is implemented by installers that can download the artifacts of a
+// tool into the cache, so that it can later be installed with Manager.Offline.
+type Fetcher interface {
+	// Fetch downloads everything needed to install the tool into the cache.
+	Fetch(tool config.Tool, m *Manager, sandbox bool) error
+}
+
+// ErrNotFetchable is returned by Fetch for tool types whose artifacts cannot be cached.
+var ErrNotFetchable = errors.New("tool type cannot be fetched")
+
+// Fetch downloads the artifacts of a tool into the cache without installing it.
+func (m *Manager) Fetch(tool config.Tool) error {
+	installer, ok := m.installers[tool.Type]
+	if !ok {
+		return fmt.Errorf("unsupported tool type: %s", tool.Type)
+	}
+	fetcher, ok := installer.(Fetcher)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFetchable, tool.Type)
+	}
+
+	if err := os.MkdirAll(m.cachePath(tool.Type), 0700); err != nil {
+		return fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	return fetcher.Fetch(tool, m, tool.IsSandboxEnabled())
+}
+
+// cacheDir returns the directory holding fetched artifacts, .box/cache by default.
+func (m *Manager) cacheDir() string {
+	if m.CacheDir != "" {
+		return m.CacheDir
+	}
+	return filepath.Join(m.RootDir, ".box", "cache")
+}
+
+// cachePath returns the cache directory of a tool type.
+func (m *Manager) cachePath(toolType string) string {
+	return filepath.Join(m.cacheDir(), toolType)
+}
+
+// packageCachePath returns the download cache of a package manager shared
+// between installs, or an empty string to use the package manager's default.
+func (m *Manager) packageCachePath(toolType string) string {
+	if m.PackageCache == "" {
+		return ""
+	}
+	return filepath.Join(m.PackageCache, toolType)
+}
+
+// scratchDir creates a temporary directory that fetchers install into to
+// populate the cache. The returned function removes it again.
+func (m *Manager) scratchDir() (string, func(), error) {
+	dir, err := os.MkdirTemp(m.TempDir, "box-fetch-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	return dir, func() { _ = os.RemoveAll(dir) }, nil
+}
+
+// copyFile copies src to dest with the given mode.
+func copyFile(src, dest string, mode os.FileMode) error {
+	in, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(filepath.Clean(dest), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,115 @@
+package installer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// fetchingInstaller installs from an artifact that Fetch places into the cache.
+type fetchingInstaller struct{}
+
+func (fetchingInstaller) Fetch(tool config.Tool, m *Manager, _ bool) error {
+	return os.WriteFile(filepath.Join(m.cachePath(tool.Type), tool.Source.String()), []byte("binary"), 0600)
+}
+
+func (fetchingInstaller) Install(tool config.Tool, m *Manager, _ bool) ([]string, error) {
+	if !m.Offline {
+		return nil, errors.New("expected an offline install")
+	}
+	return m.linkBinaries(tool, m.cachePath(tool.Type), filepath.Join(m.RootDir, ".box", "bin"), []string{tool.Source.String()})
+}
+
+func TestFetchAndInstallOffline(t *testing.T) {
+	root := t.TempDir()
+	tool := config.Tool{Type: "fake", Source: config.Source{"hello"}}
+
+	m := New(root, t.TempDir(), nil, &config.Config{})
+	m.Output = io.Discard
+	m.RegisterInstaller("fake", fetchingInstaller{})
+
+	if err := m.Fetch(tool); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".box", "cache", "fake", "hello")); err != nil {
+		t.Fatalf("expected artifact in .box/cache/fake: %v", err)
+	}
+
+	m.Offline = true
+	if err := m.Install(tool); err != nil {
+		t.Fatalf("offline Install failed: %v", err)
+	}
+
+	manifest, err := m.LoadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := manifest.Tools["hello"].Files
+	if len(files) != 1 || files[0] != filepath.Join(".box", "bin", "hello") {
+		t.Errorf("expected the cache to be excluded from the manifest, got %v", files)
+	}
+}
+
+func TestFetchNotFetchable(t *testing.T) {
+	m := New(t.TempDir(), "", nil, &config.Config{})
+	m.Output = io.Discard
+
+	err := m.Fetch(config.Tool{Type: "script", Source: config.Source{"echo hi"}})
+	if !errors.Is(err, ErrNotFetchable) {
+		t.Errorf("expected ErrNotFetchable for scripts, got %v", err)
+	}
+}
+
+func TestGoInstallOfflineLatest(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not found in PATH")
+	}
+
+	// A module cache as left by 'box fetch', including the version list
+	cache := t.TempDir()
+	download := filepath.Join(cache, "go", "cache", "download", "example.com", "hello", "@v")
+	goMod := "module example.com/hello\n\ngo 1.21\n"
+	writeTestFile(t, filepath.Join(download, "list"), "v1.0.0\n")
+	writeTestFile(t, filepath.Join(download, "v1.0.0.info"), `{"Version":"v1.0.0","Time":"2024-01-01T00:00:00Z"}`)
+	writeTestFile(t, filepath.Join(download, "v1.0.0.mod"), goMod)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{"go.mod": goMod, "main.go": "package main\n\nfunc main() {}\n"} {
+		w, err := zw.Create("example.com/hello@v1.0.0/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = io.WriteString(w, content)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(download, "v1.0.0.zip"), buf.String())
+
+	root := t.TempDir()
+	m := New(root, t.TempDir(), nil, &config.Config{})
+	m.Output = io.Discard
+	m.CacheDir = cache
+	m.Offline = true
+
+	tool := config.Tool{Type: "go", Source: config.Source{"example.com/hello"}, Version: "latest"}
+	if err := m.Install(tool); err != nil {
+		t.Fatalf("offline install of latest failed: %v", err)
+	}
+	binPath := filepath.Join(root, ".box", "bin", "hello")
+	if runtime.GOOS == "windows" {
+		binPath += ".exe"
+	}
+	if _, err := os.Stat(binPath); err != nil {
+		t.Errorf("expected the binary to be installed: %v", err)
+	}
+}
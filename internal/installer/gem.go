@@ -1,7 +1,10 @@
 package installer
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/sebakri/box/internal/config"
 )
@@ -23,10 +26,23 @@ func (i *GemInstaller) Install(tool config.Tool, m *Manager, sandbox bool) ([]st
 	if tool.Version != "" {
 		args = append(args, "-v", tool.Version)
 	}
+	dir := ""
+	if m.Offline {
+		// With --local, gem resolves the gem and its dependencies from the
+		// .gem files in the working directory
+		args = append(args, "--local")
+		dir = m.cachePath("gem")
+	} else {
+		sourceArgs, err := m.gemSourceArgs()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, sourceArgs...)
+	}
 	args = append(args, tool.Args...)
 	args = append(args, tool.Source.String())
 
-	if err := m.runCommand("gem", args, nil, "", sandbox); err != nil {
+	if err := m.runCommand("gem", args, nil, dir, sandbox); err != nil {
 		return nil, err
 	}
 
@@ -35,5 +51,58 @@ func (i *GemInstaller) Install(tool config.Tool, m *Manager, sandbox bool) ([]st
 		binaries = []string{m.detectBinaryName(tool.Source.String())}
 	}
 
-	return m.linkBinaries(gemBinDir, binDir, binaries)
+	return m.linkBinaries(tool, gemBinDir, binDir, binaries)
+}
+
+// Fetch downloads the .gem files of a gem and its dependencies into
+// .box/cache/gem by installing it into a temporary directory.
+func (i *GemInstaller) Fetch(tool config.Tool, m *Manager, sandbox bool) error {
+	m.log("Fetching %s %s (gem)...", tool.DisplayName(), tool.Version)
+
+	scratch, cleanup, err := m.scratchDir()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	args := []string{"install", "--install-dir", scratch, "--bindir", filepath.Join(scratch, "bin"), "--no-document"}
+	if tool.Version != "" {
+		args = append(args, "-v", tool.Version)
+	}
+	sourceArgs, err := m.gemSourceArgs()
+	if err != nil {
+		return err
+	}
+	args = append(args, sourceArgs...)
+	args = append(args, tool.Args...)
+	args = append(args, tool.Source.String())
+
+	if err := m.runCommand("gem", args, nil, "", sandbox); err != nil {
+		return err
+	}
+
+	// gem keeps the downloaded packages in the cache directory of the install dir
+	gems, err := os.ReadDir(filepath.Join(scratch, "cache"))
+	if err != nil {
+		return fmt.Errorf("failed to read downloaded gems: %w", err)
+	}
+	for _, g := range gems {
+		if g.IsDir() || !strings.HasSuffix(g.Name(), ".gem") {
+			continue
+		}
+		if err := copyFile(filepath.Join(scratch, "cache", g.Name()), filepath.Join(m.cachePath("gem"), g.Name()), 0600); err != nil {
+			return fmt.Errorf("failed to cache %s: %w", g.Name(), err)
+		}
+	}
+	return nil
+}
+
+// gemSourceArgs returns the arguments that replace rubygems.org with the
+// configured gem source.
+func (m *Manager) gemSourceArgs() ([]string, error) {
+	source, err := m.registryURL("gem")
+	if err != nil || source == "" {
+		return nil, err
+	}
+	return []string{"--clear-sources", "--source", source}, nil
 }
@@ -36,7 +36,25 @@ func (i *GoInstaller) Install(tool config.Tool, m *Manager, sandbox bool) ([]str
 	// Run go install with a persistent GOPATH in .box/go
 	goBinDir := filepath.Join(goDir, "bin")
 
-	newEnv := m.prepareGoEnv(goDir)
+	newEnv, err := m.goEnv(goDir)
+	if err != nil {
+		return nil, err
+	}
+	if m.Offline {
+		// Resolve modules from the fetched module cache only. Serving its
+		// download directory as a proxy also resolves "latest" and unpinned
+		// versions, which GOPROXY=off cannot.
+		cache, err := filepath.Abs(m.cachePath("go"))
+		if err != nil {
+			return nil, err
+		}
+		newEnv = append(newEnv,
+			fmt.Sprintf("GOMODCACHE=%s", cache),
+			"GOPROXY="+fileURL(filepath.Join(cache, "cache", "download")),
+			"GOSUMDB=off",
+			"GOFLAGS=-mod=mod",
+		)
+	}
 	if err := m.runCommand("go", []string{"install", source}, newEnv, "", sandbox); err != nil {
 		return nil, err
 	}
@@ -46,5 +64,56 @@ func (i *GoInstaller) Install(tool config.Tool, m *Manager, sandbox bool) ([]str
 		binaries = []string{m.detectBinaryName(source)}
 	}
 
-	return m.linkBinaries(goBinDir, binDir, binaries)
+	return m.linkBinaries(tool, goBinDir, binDir, binaries)
+}
+
+// Fetch downloads the modules of a Go tool into the cache by installing it
+// into a temporary GOPATH with GOMODCACHE pointing to the cache.
+func (i *GoInstaller) Fetch(tool config.Tool, m *Manager, sandbox bool) error {
+	m.log("Fetching %s (go)...", tool.DisplayName())
+
+	scratch, cleanup, err := m.scratchDir()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	source := tool.Source.String()
+	if tool.Version != "" {
+		source = fmt.Sprintf("%s@%s", source, tool.Version)
+	}
+
+	env, err := m.goEnv(scratch)
+	if err != nil {
+		return err
+	}
+	env = append(env, fmt.Sprintf("GOMODCACHE=%s", m.cachePath("go")))
+	return m.runCommand("go", []string{"install", source}, env, "", sandbox)
+}
+
+// fileURL returns the file:// URL of an absolute path.
+func fileURL(path string) string {
+	path = filepath.ToSlash(path)
+	// Windows paths start with a drive letter
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return "file://" + path
+}
+
+// goEnv returns the environment for go commands using goDir as GOPATH and the
+// configured module proxy.
+func (m *Manager) goEnv(goDir string) ([]string, error) {
+	env := m.prepareGoEnv(goDir)
+	if cache := m.packageCachePath("go"); cache != "" {
+		env = append(env, fmt.Sprintf("GOMODCACHE=%s", cache))
+	}
+	proxy, err := m.registryURL("go")
+	if err != nil {
+		return nil, err
+	}
+	if proxy != "" {
+		env = append(env, fmt.Sprintf("GOPROXY=%s", proxy))
+	}
+	return env, nil
 }
@@ -0,0 +1,90 @@
+package installer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// maxVerifyOutput limits the output of a verify command quoted in errors.
+const maxVerifyOutput = 200
+
+// runHooks runs the post-install commands of a tool and then its verify
+// command, in the project root with the environment and sandbox of scripts.
+func (m *Manager) runHooks(tool config.Tool) error {
+	env := m.scriptEnv()
+
+	for _, command := range tool.PostInstall {
+		m.log("Running post-install command for %s...", tool.DisplayName())
+		if err := m.runCommand("sh", []string{"-c", command}, env, m.RootDir, true); err != nil {
+			return fmt.Errorf("post-install command %q failed: %w", command, err)
+		}
+	}
+
+	if tool.Verify == nil {
+		return nil
+	}
+	return m.verifyTool(tool.Verify, env)
+}
+
+// removeNewFiles removes the files in .box that are not in before, the state
+// captured before an install, so that a tool whose hooks failed does not leave
+// untracked binaries in .box/bin.
+func (m *Manager) removeNewFiles(before map[string]time.Time) {
+	after, err := m.captureState()
+	if err != nil {
+		m.warn("Failed to clean up after the failed install: %v", err)
+		return
+	}
+
+	var added []string
+	for path := range after {
+		if _, ok := before[path]; !ok {
+			added = append(added, path)
+		}
+	}
+	// Files come after their directories, so reverse order removes them first
+	sort.Sort(sort.Reverse(sort.StringSlice(added)))
+	for _, path := range added {
+		m.log("Removing %s...", path)
+		if err := os.Remove(filepath.Join(m.RootDir, path)); err != nil {
+			m.warn("Failed to remove %s: %v", path, err)
+		}
+	}
+}
+
+// verifyTool runs a verify command and matches its output against the expected pattern.
+func (m *Manager) verifyTool(verify *config.Verify, env []string) error {
+	expect, err := regexp.Compile(verify.Expect)
+	if err != nil {
+		return fmt.Errorf("invalid verify expect pattern %q: %w", verify.Expect, err)
+	}
+
+	m.log("Verifying with %q...", verify.Command)
+	var buf bytes.Buffer
+	out := io.Writer(&buf)
+	if m.Output != nil {
+		out = io.MultiWriter(m.Output, &buf)
+	}
+	if err := m.runCommandTo(out, "sh", []string{"-c", verify.Command}, env, m.RootDir, true); err != nil {
+		return fmt.Errorf("verification failed: %q failed: %w", verify.Command, err)
+	}
+
+	if !expect.Match(buf.Bytes()) {
+		output := strings.TrimSpace(buf.String())
+		if len(output) > maxVerifyOutput {
+			output = output[:maxVerifyOutput] + "..."
+		}
+		return fmt.Errorf("verification failed: output of %q does not match `%s`: %q", verify.Command, verify.Expect, output)
+	}
+	m.log("Verified: output matches %q", verify.Expect)
+	return nil
+}
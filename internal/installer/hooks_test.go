@@ -0,0 +1,124 @@
+package installer
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/store"
+)
+
+func TestInstallHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks run with sh")
+	}
+
+	root := t.TempDir()
+	m := New(root, t.TempDir(), map[string]string{"GREETING": "hi"}, &config.Config{})
+	m.Output = io.Discard
+	m.RegisterInstaller("fake", &fakeInstaller{})
+
+	tool := config.Tool{
+		Type:        "fake",
+		Source:      config.Source{"hello"},
+		PostInstall: config.Source{"echo $GREETING > $BOX_DIR/hello.conf"},
+		Verify:      &config.Verify{Command: "cat .box/bin/hello && echo ' v1.2.3'", Expect: `v1\.2\.\d`},
+	}
+	if err := m.Install(tool); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, ".box", "hello.conf"))
+	if err != nil || strings.TrimSpace(string(data)) != "hi" {
+		t.Errorf("expected the post-install command to run with the project env, got %q (%v)", data, err)
+	}
+	manifest, err := m.LoadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := manifest.Tools["hello"].Files
+	found := false
+	for _, f := range files {
+		found = found || f == filepath.Join(".box", "hello.conf")
+	}
+	if !found {
+		t.Errorf("expected files created by post-install commands to belong to the tool, got %v", files)
+	}
+}
+
+func TestInstallHooksFailures(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks run with sh")
+	}
+
+	tests := []struct {
+		name string
+		tool config.Tool
+		want string
+	}{
+		{
+			"post-install fails",
+			config.Tool{Type: "fake", Source: config.Source{"a"}, PostInstall: config.Source{"exit 2"}},
+			`post-install command "exit 2" failed`,
+		},
+		{
+			"verify fails",
+			config.Tool{Type: "fake", Source: config.Source{"b"}, Verify: &config.Verify{Command: "false"}},
+			`verification failed: "false" failed`,
+		},
+		{
+			"output does not match",
+			config.Tool{Type: "fake", Source: config.Source{"c"}, Verify: &config.Verify{Command: "echo v1.0.0", Expect: `^v2\.`}},
+			"verification failed: output of \"echo v1.0.0\" does not match `^v2\\.`: \"v1.0.0\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(t.TempDir(), t.TempDir(), nil, &config.Config{})
+			m.Output = io.Discard
+			m.RegisterInstaller("fake", &fakeInstaller{})
+
+			err := m.Install(tt.tool)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+			manifest, err := m.LoadManifest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := manifest.Tools[tt.tool.DisplayName()]; ok {
+				t.Error("expected a tool that failed its hooks not to be recorded as installed")
+			}
+			if _, err := os.Lstat(filepath.Join(m.RootDir, ".box", "bin", tt.tool.Source.String())); !os.IsNotExist(err) {
+				t.Error("expected the binary of a tool that failed its hooks to be removed")
+			}
+			if _, err := os.Stat(filepath.Join(m.RootDir, ".box", "fake")); !os.IsNotExist(err) {
+				t.Error("expected the files of a tool that failed its hooks to be removed")
+			}
+		})
+	}
+}
+
+func TestInstallSharedVerifyFails(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks run with sh")
+	}
+
+	root := t.TempDir()
+	m := New(root, t.TempDir(), nil, &config.Config{})
+	m.Output = io.Discard
+	m.Store = &store.Store{Dir: filepath.Join(t.TempDir(), "store")}
+	m.RegisterInstaller("fake", &fakeInstaller{})
+
+	tool := config.Tool{Type: "fake", Source: config.Source{"hello"}, Version: "1.0.0", Verify: &config.Verify{Command: "false"}}
+	if err := m.Install(tool); err == nil {
+		t.Fatal("expected the install to fail")
+	}
+	if _, err := os.Lstat(filepath.Join(root, ".box", "bin", "hello")); !os.IsNotExist(err) {
+		t.Error("expected the link into the store to be removed")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -13,6 +14,8 @@ import (
 	"time"
 
 	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/logging"
+	"github.com/sebakri/box/internal/store"
 )
 
 // Manager handles tool installations and environment setup.
@@ -22,6 +25,14 @@ type Manager struct {
 	Env          map[string]string
 	Output       io.Writer
 	GlobalConfig *config.Config
+	ConfigFile   string                     // Path of the configuration file, defaults to box.yml in RootDir
+	Store        *store.Store               // Shared tool store, nil to install into .box only
+	CacheDir     string                     // Directory of fetched artifacts, defaults to .box/cache in RootDir
+	PackageCache string                     // Download caches of the package managers shared between installs, empty for their defaults
+	Offline      bool                       // Install from CacheDir only, without network access
+	Registries   map[string]config.Registry // Package registry mirrors keyed by tool type
+	Level        slog.Leveler               // Minimum level of messages written to Output, defaults to info
+	LogHandler   slog.Handler               // Additional handler of all messages, such as a JSON log file
 
 	// installers map tool types to their implementation
 	installers map[string]Installer
@@ -29,12 +40,16 @@ type Manager struct {
 
 // ToolManifest tracks metadata and files installed for a specific tool.
 type ToolManifest struct {
-	Type      string    `json:"type"`
-	Source    string    `json:"source"`
-	Version   string    `json:"version,omitempty"`
-	Files     []string  `json:"files"`
-	Installed time.Time `json:"installed"`
-	Updated   time.Time `json:"updated"`
+	Type            string                `json:"type"`
+	Source          string                `json:"source"`
+	Version         string                `json:"version,omitempty"`
+	ResolvedVersion string                `json:"resolved_version,omitempty"` // Version that was actually installed, e.g. for "latest"
+	Args            []string              `json:"args,omitempty"`
+	Store           string                `json:"store,omitempty"` // Key of the shared store entry the files link to
+	Files           []string              `json:"files"`
+	Integrity       map[string]FileRecord `json:"integrity,omitempty"` // Records of the files, keyed like Files
+	Installed       time.Time             `json:"installed"`
+	Updated         time.Time             `json:"updated"`
 }
 
 // Manifest represents the persistent state of installed tools.
@@ -61,19 +76,66 @@ func New(rootDir string, tempDir string, env map[string]string, cfg *config.Conf
 	return m
 }
 
+// configFileName returns the name of the configuration file within the project
+// root. If ConfigFile is in another directory, such as a workspace member
+// installing into the workspace root, the root's own configuration is used.
+func (m *Manager) configFileName() string {
+	if m.ConfigFile == "" {
+		return "box.yml"
+	}
+	dir, _ := filepath.Abs(filepath.Dir(m.ConfigFile))
+	root, _ := filepath.Abs(m.RootDir)
+	if dir != root {
+		if path := config.FindIn(root); path != "" {
+			return filepath.Base(path)
+		}
+	}
+	return filepath.Base(m.ConfigFile)
+}
+
 // RegisterInstaller adds a new installer for a tool type.
 func (m *Manager) RegisterInstaller(toolType string, installer Installer) {
 	m.installers[toolType] = installer
 }
 
+// logger returns the logger writing to Output and LogHandler.
+func (m *Manager) logger() *slog.Logger {
+	return slog.New(logging.Multi(logging.NewHandler(m.Output, m.Level), m.LogHandler))
+}
+
+// log reports progress at info level.
 func (m *Manager) log(format string, a ...any) {
-	if m.Output != nil {
-		_, _ = fmt.Fprintf(m.Output, format+"\n", a...)
-	}
+	m.logger().Info(fmt.Sprintf(format, a...))
 }
 
-// Install installs a tool based on its configuration.
+// warn reports a problem that does not stop the installation.
+func (m *Manager) warn(format string, a ...any) {
+	m.logger().Warn(fmt.Sprintf(format, a...))
+}
+
+// Install installs a tool based on its configuration. The installer output is
+// also written to an install log in .box/logs.
 func (m *Manager) Install(tool config.Tool) error {
+	// The project-wide shims setting applies to every tool
+	if m.GlobalConfig != nil && m.GlobalConfig.Shims {
+		tool.Shim = true
+	}
+	logged, finish := m.withLog(tool)
+	log := logged.logger().With("tool", tool.DisplayName())
+	log.Debug("install started", "type", tool.Type, "root", m.RootDir, "temp", m.TempDir, "cache", m.cacheDir(), "offline", m.Offline)
+
+	start := time.Now()
+	err := logged.install(tool)
+	if err != nil {
+		log.Debug("install failed", "duration", time.Since(start).Round(time.Millisecond), "error", err)
+	} else {
+		log.Debug("install finished", "duration", time.Since(start).Round(time.Millisecond))
+	}
+	finish(err)
+	return err
+}
+
+func (m *Manager) install(tool config.Tool) error {
 	boxDir := filepath.Join(m.RootDir, ".box")
 	binDir := filepath.Join(boxDir, "bin")
 
@@ -92,14 +154,36 @@ func (m *Manager) Install(tool config.Tool) error {
 	if !ok {
 		return fmt.Errorf("unsupported tool type: %s", tool.Type)
 	}
+	if err := m.checkBinaryConflicts(tool); err != nil {
+		return err
+	}
+
+	// Scripts can write anywhere in .box, so they are never shared. Entries
+	// are keyed by the declared version, so floating versions like latest are
+	// installed into the project to pick up new releases.
+	if m.Store != nil && tool.Type != "script" && exactVersion.MatchString(tool.Version) {
+		return m.installShared(tool, before)
+	}
 
 	// Determine if sandbox is enabled for this tool (always true for scripts)
 	sandboxEnabled := tool.IsSandboxEnabled()
 
-	managedFiles, err := installer.Install(tool, m, sandboxEnabled)
+	var managedFiles []string
+	installed := m
+	if needsVariant(tool) {
+		managedFiles, installed, err = m.installVariant(tool, installer, sandboxEnabled)
+	} else {
+		managedFiles, err = installer.Install(tool, m, sandboxEnabled)
+	}
 	if err != nil {
 		return err
 	}
+	// Files created by hooks belong to the tool, so they run before the state is captured
+	if err := m.runHooks(tool); err != nil {
+		m.removeNewFiles(before)
+		return err
+	}
+	version := m.resolveVersion(tool, installer, installed)
 
 	// Capture state after install and find new files
 	after, err := m.captureState()
@@ -111,9 +195,13 @@ func (m *Manager) Install(tool config.Tool) error {
 	for _, f := range managedFiles {
 		newFilesMap[f] = true
 	}
-	for path := range after {
-		if _, ok := before[path]; !ok {
+	var touched []string
+	for path, modTime := range after {
+		previous, ok := before[path]
+		if !ok {
 			newFilesMap[path] = true
+		} else if !modTime.Equal(previous) {
+			touched = append(touched, path)
 		}
 	}
 
@@ -123,18 +211,19 @@ func (m *Manager) Install(tool config.Tool) error {
 	}
 	sort.Strings(newFileList)
 
-	return m.updateManifest(tool, newFileList)
+	return m.updateManifest(tool, newFileList, version, "", touched)
 }
 
-func (m *Manager) captureState() (map[string]bool, error) {
-	state := make(map[string]bool)
+// captureState returns the modification times of all files in .box.
+func (m *Manager) captureState() (map[string]time.Time, error) {
+	state := make(map[string]time.Time)
 	boxDir := filepath.Join(m.RootDir, ".box")
 
 	if _, err := os.Stat(boxDir); os.IsNotExist(err) {
 		return state, nil
 	}
 
-	err := filepath.Walk(boxDir, func(path string, _ os.FileInfo, err error) error {
+	err := filepath.Walk(boxDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -147,14 +236,21 @@ func (m *Manager) captureState() (map[string]bool, error) {
 		if rel == ".box" || rel == filepath.Join(".box", "manifest.json") {
 			return nil
 		}
-		state[rel] = true
+		// Fetched artifacts and install logs are shared by all tools and never belong to one
+		if rel == filepath.Join(".box", "cache") || rel == filepath.Join(".box", "logs") {
+			return filepath.SkipDir
+		}
+		state[rel] = info.ModTime()
 		return nil
 	})
 
 	return state, err
 }
 
-func (m *Manager) updateManifest(tool config.Tool, files []string) error {
+// updateManifest records the files of an installed tool. Files of other tools
+// that the install touched, such as shared package manager state, are
+// recorded again so that they are not reported as modified.
+func (m *Manager) updateManifest(tool config.Tool, files []string, resolvedVersion, storeKey string, touched []string) error {
 	manifestPath := filepath.Join(m.RootDir, ".box", "manifest.json")
 	manifest := Manifest{Tools: make(map[string]ToolManifest)}
 
@@ -185,13 +281,40 @@ func (m *Manager) updateManifest(tool config.Tool, files []string) error {
 		files = newFileList
 	}
 
+	// Drop files of a previous install that no longer exist
+	existingFiles := files[:0]
+	for _, f := range files {
+		if _, err := os.Lstat(filepath.Join(m.RootDir, f)); err == nil {
+			existingFiles = append(existingFiles, f)
+		}
+	}
+	files = existingFiles
+
+	for other, info := range manifest.Tools {
+		if other == name || info.Integrity == nil {
+			continue
+		}
+		for _, f := range touched {
+			if _, ok := info.Integrity[f]; !ok {
+				continue
+			}
+			if record, err := recordFile(filepath.Join(m.RootDir, f)); err == nil {
+				info.Integrity[f] = record
+			}
+		}
+	}
+
 	manifest.Tools[name] = ToolManifest{
-		Type:      tool.Type,
-		Source:    tool.Source.String(),
-		Version:   tool.Version,
-		Files:     files,
-		Installed: installed,
-		Updated:   now,
+		Type:            tool.Type,
+		Source:          tool.Source.String(),
+		Version:         tool.Version,
+		ResolvedVersion: resolvedVersion,
+		Args:            tool.Args,
+		Store:           storeKey,
+		Files:           files,
+		Integrity:       m.recordFiles(files),
+		Installed:       installed,
+		Updated:         now,
 	}
 
 	data, err := json.MarshalIndent(manifest, "", "  ")
@@ -241,11 +364,12 @@ func (m *Manager) Uninstall(name string) error {
 		// This prevents path traversal attacks if the manifest is tampered with.
 		rel, err := filepath.Rel(m.RootDir, fullPath)
 		if err != nil || strings.HasPrefix(rel, "..") || strings.HasPrefix(rel, "/") || (runtime.GOOS == "windows" && strings.Contains(rel, ":")) {
-			m.log("Security Warning: Skipping deletion of unsafe path %s", fullPath)
+			m.warn("Skipping deletion of unsafe path %s", fullPath)
 			continue
 		}
 
-		info, err := os.Stat(fullPath)
+		// Links whose target was removed first must still be deleted
+		info, err := os.Lstat(fullPath)
 		if err != nil {
 			continue
 		}
@@ -327,46 +451,82 @@ func (m *Manager) detectBinaryName(source string) string {
 	return binaryName
 }
 
-func (m *Manager) linkBinaries(goBinDir, binDir string, binaries []string) ([]string, error) {
+// linkBinaries links the binaries of the tool found in srcDir into binDir.
+func (m *Manager) linkBinaries(tool config.Tool, srcDir, binDir string, binaries []string) ([]string, error) {
 	createdFiles := []string{}
 	for _, name := range binaries {
-		srcBinary, err := m.findBinary(goBinDir, name)
+		srcBinary, err := m.findBinary(srcDir, name)
 		if err != nil {
 			return nil, err
 		}
 
-		destBinary := filepath.Join(binDir, name)
-		if runtime.GOOS == "windows" && !strings.HasSuffix(destBinary, ".exe") {
-			destBinary += ".exe"
+		dest, err := m.linkBinary(tool, name, srcBinary, binDir, false)
+		if err != nil {
+			return nil, err
 		}
+		createdFiles = append(createdFiles, dest)
+	}
+	return createdFiles, nil
+}
 
-		_ = os.Remove(destBinary)
+// linkBinary makes the binary at target available in binDir under the name
+// the tool exposes it as: as a shim if the tool uses shims, else as a
+// symlink, or as a copy if symlinks are not supported. Absolute links are used
+// for targets outside the project. It returns the path of the new file
+// relative to the project root.
+func (m *Manager) linkBinary(tool config.Tool, name, target, binDir string, absolute bool) (string, error) {
+	// Renames and templates apply to the name without the .exe suffix
+	base := strings.TrimSuffix(name, ".exe")
+	linkName, err := tool.LinkName(base)
+	if err != nil {
+		return "", err
+	}
+	ext := name[len(base):]
+	if runtime.GOOS == "windows" {
+		ext = ".exe"
+	}
 
-		relPath, err := filepath.Rel(binDir, srcBinary)
-		if err == nil {
-			m.log("Symlinking %s to %s...", relPath, destBinary)
-			err = os.Symlink(relPath, destBinary)
-			if err == nil {
-				relToRoot, _ := filepath.Rel(m.RootDir, destBinary)
-				createdFiles = append(createdFiles, relToRoot)
-				continue
-			}
-			m.log("Symlink failed, falling back to copy: %v", err)
+	link := target
+	if !absolute {
+		if link, err = filepath.Rel(binDir, target); err != nil {
+			return "", fmt.Errorf("failed to link binary %s: %w", name, err)
 		}
+	}
 
-		m.log("Copying %s to %s...", srcBinary, destBinary)
-		input, err := os.ReadFile(filepath.Clean(srcBinary))
-		if err != nil {
-			return nil, fmt.Errorf("failed to read installed binary %s: %w", srcBinary, err)
+	if tool.Shim {
+		dest := filepath.Join(binDir, linkName+shimExt)
+		m.log("Writing shim %s for %s...", dest, target)
+		if err := m.writeShim(dest, link); err != nil {
+			return "", err
 		}
+		return filepath.Rel(m.RootDir, dest)
+	}
+
+	dest := filepath.Join(binDir, linkName+ext)
+	_ = os.Remove(dest)
 
-		if err := os.WriteFile(destBinary, input, 0600); err != nil {
-			return nil, fmt.Errorf("failed to copy binary to .box/bin: %w", err)
+	m.log("Symlinking %s to %s...", link, dest)
+	if err := os.Symlink(link, dest); err != nil {
+		m.warn("Symlink failed, falling back to copy: %v", err)
+		m.log("Copying %s to %s...", target, dest)
+		if err := copyExecutable(target, dest); err != nil {
+			return "", fmt.Errorf("failed to copy binary to .box/bin: %w", err)
 		}
-		relToRoot, _ := filepath.Rel(m.RootDir, destBinary)
-		createdFiles = append(createdFiles, relToRoot)
 	}
-	return createdFiles, nil
+	return filepath.Rel(m.RootDir, dest)
+}
+
+// copyExecutable copies a binary and keeps its permissions, so that it stays executable.
+func copyExecutable(src, dest string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if err := copyFile(src, dest, info.Mode().Perm()); err != nil {
+		return err
+	}
+	// The permissions of new files are reduced by the umask
+	return os.Chmod(dest, info.Mode().Perm())
 }
 
 func (m *Manager) findBinary(searchDir, name string) (string, error) {
@@ -435,68 +595,6 @@ func (m *Manager) AllowDirenv() error {
 	return m.runCommand("direnv", []string{"allow"}, nil, m.RootDir, false)
 }
 
-// GenerateDockerfile creates a Dockerfile for the project.
-// GenerateDockerfile creates a Dockerfile for the project.
-func (m *Manager) GenerateDockerfile() error {
-	dockerfilePath := filepath.Join(m.RootDir, "Dockerfile")
-	content := `FROM debian:bookworm-slim
-
-# Package manager feature flags
-ARG INSTALL_GO=true
-ARG INSTALL_NODE=true
-ARG INSTALL_CARGO=true
-ARG INSTALL_UV=true
-ARG INSTALL_RUBY=true
-ARG INSTALL_PIP=true
-
-# Install system dependencies and selected package managers
-RUN apt-get update && \
-    PACKAGES="curl ca-certificates git build-essential direnv" && \
-    if [ "$INSTALL_NODE" = "true" ]; then PACKAGES="$PACKAGES nodejs npm"; fi && \
-    if [ "$INSTALL_RUBY" = "true" ]; then PACKAGES="$PACKAGES ruby-full"; fi && \
-    if [ "$INSTALL_PIP" = "true" ]; then PACKAGES="$PACKAGES python3-pip"; fi && \
-    apt-get install -y --no-install-recommends $PACKAGES && \
-    rm -rf /var/lib/apt/lists/*
-
-# Install latest Go if enabled
-RUN if [ "$INSTALL_GO" = "true" ]; then \
-    curl -LsSf https://go.dev/dl/go1.24.0.linux-amd64.tar.gz | tar -C /usr/local -xz; \
-    fi
-ENV PATH="/usr/local/go/bin:${PATH}"
-
-# Install cargo-binstall if enabled
-RUN if [ "$INSTALL_CARGO" = "true" ]; then \
-    curl -L --proto '=https' --tlsv1.2 -sSf https://raw.githubusercontent.com/cargo-bins/cargo-binstall/main/install.sh | sh && \
-    if [ -f "$HOME/.cargo/bin/cargo-binstall" ]; then mv "$HOME/.cargo/bin/cargo-binstall" /usr/local/bin/; fi; \
-    fi
-
-# Install uv globally if enabled
-RUN if [ "$INSTALL_UV" = "true" ]; then \
-    curl -LsSf https://astral.sh/uv/install.sh | UV_INSTALL_DIR=/usr/local/bin sh; \
-    fi
-
-# Copy box binary
-COPY --link --chmod=755 box /usr/local/bin/box
-
-# Set up user and workspace
-RUN useradd -m -s /bin/bash box
-USER box
-WORKDIR /home/box
-
-# Copy configuration and install tools
-COPY --chown=box:box box.yml .
-ENV CGO_ENABLED=0
-RUN box install --non-interactive
-
-# Add box binaries to PATH
-ENV PATH="/home/box/.box/bin:${PATH}"
-
-CMD ["/bin/bash"]
-`
-	m.log("Generating Dockerfile...")
-	return os.WriteFile(dockerfilePath, []byte(content), 0600)
-}
-
 func isDigit(s string) bool {
 	if s == "" {
 		return false
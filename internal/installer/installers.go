@@ -1,11 +1,16 @@
 package installer
 
 import (
+	"context"
+	"io"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/logging"
 	"github.com/sebakri/box/internal/sandbox"
 )
 
@@ -31,8 +36,24 @@ var SupportedTools = map[string]ToolType{
 	"script": {Name: "sh", Installer: &ScriptInstaller{}},
 }
 
+// SupportedTypeNames returns the sorted names of all supported tool types.
+func SupportedTypeNames() []string {
+	names := make([]string, 0, len(SupportedTools))
+	for name := range SupportedTools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // runCommand is a helper to run shell commands with consistent output redirection and environment setup.
 func (m *Manager) runCommand(name string, args []string, env []string, dir string, useSandbox bool) error {
+	return m.runCommandTo(m.Output, name, args, env, dir, useSandbox)
+}
+
+// runCommandTo runs a command like runCommand, but writes its output to out
+// instead of Output. Log messages are still written to Output.
+func (m *Manager) runCommandTo(out io.Writer, name string, args []string, env []string, dir string, useSandbox bool) error {
 	cmdName := name
 	cmdArgs := args
 
@@ -41,7 +62,7 @@ func (m *Manager) runCommand(name string, args []string, env []string, dir strin
 	tempCmd := exec.Command(name, args...)
 
 	if useSandbox {
-		cmdName, cmdArgs = sandbox.Apply(tempCmd, name, args, m.RootDir, m.TempDir)
+		cmdName, cmdArgs = sandbox.Apply(tempCmd, name, args, m.RootDir, m.TempDir, m.logger())
 	}
 
 	//nolint:gosec
@@ -50,13 +71,43 @@ func (m *Manager) runCommand(name string, args []string, env []string, dir strin
 	if dir != "" {
 		cmd.Dir = dir
 	}
-	cmd.Stdout = m.Output
-	cmd.Stderr = m.Output
+	cmd.Stdout = out
+	cmd.Stderr = out
 
 	if len(env) > 0 {
 		cmd.Env = append(os.Environ(), env...)
 	}
 
-	m.log("Running: %s %s", cmdName, strings.Join(cmdArgs, " "))
-	return cmd.Run()
+	m.log("Running: %s %s", cmdName, strings.Join(redactArgs(cmdArgs), " "))
+	log := m.logger()
+	if path, err := exec.LookPath(cmdName); err == nil {
+		log.Debug("resolved command", "name", cmdName, "path", path)
+	}
+	log.Debug("command environment", "dir", cmd.Dir, "sandbox", useSandbox, "env", logging.RedactEnv(addedEnv(env)))
+	log.Log(context.Background(), logging.LevelTrace, "full command environment", "env", logging.RedactEnv(cmd.Environ()))
+
+	start := time.Now()
+	err := cmd.Run()
+	if err != nil {
+		log.Debug("command failed", "name", cmdName, "duration", time.Since(start).Round(time.Millisecond), "error", err)
+	} else {
+		log.Debug("command finished", "name", cmdName, "duration", time.Since(start).Round(time.Millisecond))
+	}
+	return err
+}
+
+// addedEnv returns the variables of env that are not inherited unchanged from
+// the environment of box.
+func addedEnv(env []string) []string {
+	inherited := make(map[string]bool)
+	for _, e := range os.Environ() {
+		inherited[e] = true
+	}
+	var added []string
+	for _, e := range env {
+		if !inherited[e] {
+			added = append(added, e)
+		}
+	}
+	return added
 }
@@ -0,0 +1,146 @@
+package installer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// KeepLogs is the number of install logs kept per tool.
+const KeepLogs = 5
+
+// logTimeLayout is the timestamp in log file names. It sorts chronologically.
+const logTimeLayout = "20060102-150405.000000000"
+
+// logMarker starts the header and trailer lines box writes to install logs,
+// so they are told apart from the output of installers.
+const logMarker = "#box# "
+
+// unsafeLogChars matches characters that are replaced in log file names.
+var unsafeLogChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func (m *Manager) logDir() string {
+	return filepath.Join(m.RootDir, ".box", "logs")
+}
+
+// logPrefix returns the file name prefix of the logs of a tool.
+func logPrefix(name string) string {
+	return unsafeLogChars.ReplaceAllString(name, "_") + "-"
+}
+
+// withLog returns a copy of m whose output is also written to a new install
+// log of the tool in .box/logs. The returned function records the outcome,
+// closes the log and removes old logs of the tool. If the log cannot be
+// created, m is returned unchanged.
+func (m *Manager) withLog(tool config.Tool) (*Manager, func(err error)) {
+	name := tool.DisplayName()
+	if err := os.MkdirAll(m.logDir(), 0700); err != nil {
+		m.warn("Failed to create log directory: %v", err)
+		return m, func(error) {}
+	}
+
+	start := time.Now()
+	path := filepath.Join(m.logDir(), logPrefix(name)+start.Format(logTimeLayout)+".log")
+	f, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		m.warn("Failed to create install log: %v", err)
+		return m, func(error) {}
+	}
+	_, _ = fmt.Fprintf(f, logMarker+"install %s (%s) at %s\n", name, tool.Type, start.Format(time.RFC3339))
+
+	logged := *m
+	if m.Output != nil {
+		logged.Output = io.MultiWriter(m.Output, f)
+	} else {
+		logged.Output = f
+	}
+
+	return &logged, func(err error) {
+		if err != nil {
+			_, _ = fmt.Fprintf(f, logMarker+"failed after %s: %v\n", time.Since(start).Round(time.Millisecond), err)
+		} else {
+			_, _ = fmt.Fprintf(f, logMarker+"finished after %s\n", time.Since(start).Round(time.Millisecond))
+		}
+		_ = f.Close()
+		m.rotateLogs(name)
+	}
+}
+
+// Logs returns the install logs of a tool, oldest first.
+func (m *Manager) Logs(name string) ([]string, error) {
+	entries, err := os.ReadDir(m.logDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	prefix := logPrefix(name)
+	var logs []string
+	for _, e := range entries {
+		stamp, ok := strings.CutPrefix(e.Name(), prefix)
+		if !ok || e.IsDir() {
+			continue
+		}
+		// The prefix of one tool can be the start of another tool's name
+		if _, err := time.Parse(logTimeLayout, strings.TrimSuffix(stamp, ".log")); err != nil {
+			continue
+		}
+		logs = append(logs, filepath.Join(m.logDir(), e.Name()))
+	}
+	sort.Strings(logs)
+	return logs, nil
+}
+
+// LatestLog returns the path of the most recent install log of a tool, or ""
+// if there is none.
+func (m *Manager) LatestLog(name string) (string, error) {
+	logs, err := m.Logs(name)
+	if err != nil || len(logs) == 0 {
+		return "", err
+	}
+	return logs[len(logs)-1], nil
+}
+
+// LogTail returns up to n last lines of the most recent install log of a tool.
+func (m *Manager) LogTail(name string, n int) []string {
+	path, err := m.LatestLog(name)
+	if err != nil || path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		// Skip the header and trailer written by box
+		if line = strings.TrimRight(line, "\r"); line != "" && !strings.HasPrefix(line, logMarker) {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// rotateLogs removes all but the KeepLogs most recent logs of a tool.
+func (m *Manager) rotateLogs(name string) {
+	logs, err := m.Logs(name)
+	if err != nil || len(logs) <= KeepLogs {
+		return
+	}
+	for _, path := range logs[:len(logs)-KeepLogs] {
+		_ = os.Remove(path)
+	}
+}
@@ -0,0 +1,119 @@
+package installer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/logging"
+)
+
+func TestInstallLogs(t *testing.T) {
+	root := t.TempDir()
+	m := New(root, t.TempDir(), nil, &config.Config{})
+	m.Output = io.Discard
+	m.RegisterInstaller("fake", &fakeInstaller{})
+	m.RegisterInstaller("failing", failingInstaller{})
+
+	tool := config.Tool{Type: "fake", Source: config.Source{"hello"}, Alias: "org/hello"}
+	for i := 0; i < KeepLogs+2; i++ {
+		if err := m.Install(tool); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+	}
+
+	logs, err := m.Logs("org/hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != KeepLogs {
+		t.Errorf("expected %d logs after rotation, got %d", KeepLogs, len(logs))
+	}
+	data, err := os.ReadFile(logs[len(logs)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Symlinking") || !strings.Contains(string(data), logMarker+"finished") {
+		t.Errorf("expected the installer output in the log, got:\n%s", data)
+	}
+
+	// A tool whose name starts with the name of another tool has its own logs
+	if err := m.Install(config.Tool{Type: "failing", Source: config.Source{"x"}, Alias: "org/hello-world"}); err == nil {
+		t.Fatal("expected the failing install to fail")
+	}
+	if logs, _ := m.Logs("org/hello"); len(logs) != KeepLogs {
+		t.Errorf("expected the logs of org/hello-world to be separate, got %v", logs)
+	}
+	if log, _ := m.LatestLog("org/hello-world"); log == "" {
+		t.Errorf("expected a log for the failed tool")
+	}
+	if tail := m.LogTail("org/hello", 2); len(tail) != 1 || !strings.Contains(tail[0], "Symlinking") {
+		t.Errorf("expected the last installer lines without the trailer, got %v", tail)
+	}
+
+	manifest, err := m.LoadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range manifest.Tools["org/hello"].Files {
+		if strings.HasPrefix(file, filepath.Join(".box", "logs")) {
+			t.Errorf("expected logs to be excluded from the manifest, got %s", file)
+		}
+	}
+}
+
+func TestLogTail(t *testing.T) {
+	m := New(t.TempDir(), t.TempDir(), nil, &config.Config{})
+	m.Output = io.Discard
+
+	logged, finish := m.withLog(config.Tool{Type: "script", Alias: "gen"})
+	_, _ = io.WriteString(logged.Output, "# generated by gen\nerror: boom\n")
+	finish(errors.New("exit status 1"))
+
+	if tail := m.LogTail("gen", 5); !slices.Equal(tail, []string{"# generated by gen", "error: boom"}) {
+		t.Errorf("expected the installer output without the header and trailer, got %q", tail)
+	}
+}
+
+func TestRunCommandLogLevels(t *testing.T) {
+	var out, file bytes.Buffer
+	m := &Manager{
+		Output:     &out,
+		Level:      slog.LevelWarn,
+		LogHandler: slog.NewJSONHandler(&file, &slog.HandlerOptions{Level: logging.LevelTrace}),
+	}
+
+	env := []string{"BOX_TEST_TOKEN=s3cret", "BOX_TEST_DIR=/tmp"}
+	if err := m.runCommand("true", nil, env, "", false); err != nil {
+		t.Skipf("true is not available: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no messages below the warning level, got %q", out.String())
+	}
+
+	records := file.String()
+	for _, msg := range []string{`"msg":"Running: true "`, `"msg":"command environment"`, `"msg":"command finished"`, `"duration"`, "BOX_TEST_DIR=/tmp"} {
+		if !strings.Contains(records, msg) {
+			t.Errorf("expected %s in the log handler records, got:\n%s", msg, records)
+		}
+	}
+	if strings.Contains(records, "s3cret") {
+		t.Errorf("expected the token to be redacted, got:\n%s", records)
+	}
+
+	out.Reset()
+	m.Level = slog.LevelDebug
+	if err := m.runCommand("true", nil, env, "", false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "debug: command finished") || strings.Contains(out.String(), "trace:") {
+		t.Errorf("expected debug but no trace messages, got %q", out.String())
+	}
+}
@@ -23,8 +23,23 @@ func (i *NpmInstaller) Install(tool config.Tool, m *Manager, sandbox bool) ([]st
 	npmBinDir := filepath.Join(npmDir, "bin")
 	binDir := filepath.Join(boxDir, "bin")
 
+	regArgs, env, cleanup, err := m.npmRegistry()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
 	// npm install --prefix .box/npm -g <package>
-	if err := m.runCommand("npm", []string{"install", "--prefix", npmDir, "-g", source}, nil, "", sandbox); err != nil {
+	args := []string{"install", "--prefix", npmDir, "-g"}
+	if m.Offline {
+		args = append(args, "--offline", "--cache", m.cachePath("npm"))
+	} else if cache := m.packageCachePath("npm"); cache != "" {
+		args = append(args, "--cache", cache)
+	}
+	args = append(args, regArgs...)
+	args = append(args, source)
+
+	if err := m.runCommand("npm", args, env, "", sandbox); err != nil {
 		return nil, err
 	}
 
@@ -33,5 +48,32 @@ func (i *NpmInstaller) Install(tool config.Tool, m *Manager, sandbox bool) ([]st
 		binaries = []string{m.detectBinaryName(source)}
 	}
 
-	return m.linkBinaries(npmBinDir, binDir, binaries)
+	return m.linkBinaries(tool, npmBinDir, binDir, binaries)
+}
+
+// Fetch downloads the package and its dependencies into the npm cache in
+// .box/cache/npm by installing it into a temporary prefix.
+func (i *NpmInstaller) Fetch(tool config.Tool, m *Manager, sandbox bool) error {
+	source := tool.Source.String()
+	if tool.Version != "" {
+		source = fmt.Sprintf("%s@%s", source, tool.Version)
+	}
+	m.log("Fetching %s (npm)...", tool.DisplayName())
+
+	scratch, cleanup, err := m.scratchDir()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	regArgs, env, cleanupRegistry, err := m.npmRegistry()
+	if err != nil {
+		return err
+	}
+	defer cleanupRegistry()
+
+	args := []string{"install", "--prefix", scratch, "-g", "--cache", m.cachePath("npm")}
+	args = append(args, regArgs...)
+	args = append(args, source)
+	return m.runCommand("npm", args, env, "", sandbox)
 }
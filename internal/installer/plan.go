@@ -0,0 +1,125 @@
+package installer
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// DependencyError is the error of tools skipped because a tool they depend
+// on could not be installed.
+type DependencyError struct {
+	Dependency string
+}
+
+func (e DependencyError) Error() string {
+	return fmt.Sprintf("dependency %s failed to install", e.Dependency)
+}
+
+type planState int
+
+const (
+	planPending planState = iota
+	planRunning
+	planDone
+	planFailed
+)
+
+// Plan schedules the installation of tools in dependency order. Tools are
+// handed out by Next once all their dependencies are installed, so tools on
+// independent branches can be installed one after the other or concurrently.
+// Tools that depend on a failed tool are skipped. A Plan is safe for use by
+// concurrent workers.
+type Plan struct {
+	mu    sync.Mutex
+	tools []config.Tool
+	state map[string]planState
+}
+
+// NewPlan orders the tools by their dependencies. It fails if a tool depends
+// on an unknown tool or the dependencies form a cycle.
+func NewPlan(tools []config.Tool) (*Plan, error) {
+	ordered, err := config.InstallOrder(tools)
+	if err != nil {
+		return nil, err
+	}
+	p := &Plan{tools: ordered, state: make(map[string]planState, len(ordered))}
+	for _, t := range ordered {
+		p.state[t.DisplayName()] = planPending
+	}
+	return p, nil
+}
+
+// Tools returns all tools in install order.
+func (p *Plan) Tools() []config.Tool {
+	return p.tools
+}
+
+// Next returns the first pending tool whose dependencies are installed and
+// marks it as running. It returns false if no tool is ready, either because
+// all tools are finished or because the remaining ones wait for running tools.
+func (p *Plan) Next() (config.Tool, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, t := range p.tools {
+		if p.state[t.DisplayName()] != planPending {
+			continue
+		}
+		ready := true
+		for _, dep := range t.DependsOn {
+			if p.state[dep] != planDone {
+				ready = false
+				break
+			}
+		}
+		if ready {
+			p.state[t.DisplayName()] = planRunning
+			return t, true
+		}
+	}
+	return config.Tool{}, false
+}
+
+// Finish records the outcome of installing a tool. If it failed, the pending
+// tools that depend on it, directly or indirectly, are skipped and returned
+// in install order.
+func (p *Plan) Finish(name string, err error) []config.Tool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err == nil {
+		p.state[name] = planDone
+		return nil
+	}
+	p.state[name] = planFailed
+
+	var skipped []config.Tool
+	failed := map[string]bool{name: true}
+	// Dependents come after their dependencies, so one pass finds all of them
+	for _, t := range p.tools {
+		if p.state[t.DisplayName()] != planPending {
+			continue
+		}
+		for _, dep := range t.DependsOn {
+			if failed[dep] {
+				failed[t.DisplayName()] = true
+				p.state[t.DisplayName()] = planFailed
+				skipped = append(skipped, t)
+				break
+			}
+		}
+	}
+	return skipped
+}
+
+// Done reports whether all tools are installed, failed or skipped.
+func (p *Plan) Done() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, state := range p.state {
+		if state == planPending || state == planRunning {
+			return false
+		}
+	}
+	return true
+}
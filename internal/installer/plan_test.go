@@ -0,0 +1,112 @@
+package installer
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+func TestPlan(t *testing.T) {
+	tools := []config.Tool{
+		{Type: "script", Alias: "lint", Source: config.Source{"task lint"}, DependsOn: []string{"setup"}},
+		{Type: "script", Alias: "setup", Source: config.Source{"task setup"}, DependsOn: []string{"task"}},
+		{Type: "go", Alias: "task", Source: config.Source{"github.com/go-task/task/v3/cmd/task"}},
+		{Type: "npm", Source: config.Source{"prettier"}},
+	}
+	plan, err := NewPlan(tools)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	next := func() string {
+		t.Helper()
+		tool, ok := plan.Next()
+		if !ok {
+			return ""
+		}
+		return tool.DisplayName()
+	}
+
+	// Independent tools are handed out while a dependency is still running
+	if name := next(); name != "task" {
+		t.Fatalf("expected task first, got %q", name)
+	}
+	if name := next(); name != "prettier" {
+		t.Fatalf("expected prettier while task is running, got %q", name)
+	}
+	if name := next(); name != "" {
+		t.Fatalf("expected no tool to be ready, got %q", name)
+	}
+
+	if skipped := plan.Finish("prettier", nil); len(skipped) != 0 {
+		t.Errorf("expected no skipped tools, got %v", skipped)
+	}
+	skipped := plan.Finish("task", errors.New("boom"))
+	if len(skipped) != 2 || skipped[0].DisplayName() != "setup" || skipped[1].DisplayName() != "lint" {
+		t.Errorf("expected setup and lint to be skipped, got %v", skipped)
+	}
+	if name := next(); name != "" {
+		t.Errorf("expected no tool after the failure, got %q", name)
+	}
+	if !plan.Done() {
+		t.Error("expected the plan to be done")
+	}
+}
+
+func TestPlanCycle(t *testing.T) {
+	_, err := NewPlan([]config.Tool{
+		{Type: "script", Alias: "a", DependsOn: []string{"b"}},
+		{Type: "script", Alias: "b", DependsOn: []string{"a"}},
+	})
+	if err == nil || err.Error() != "dependency cycle: a -> b -> a" {
+		t.Errorf("expected a dependency cycle error, got %v", err)
+	}
+}
+
+func TestPlanConcurrent(t *testing.T) {
+	tools := []config.Tool{
+		{Type: "go", Alias: "task", Source: config.Source{"github.com/go-task/task/v3/cmd/task"}},
+		{Type: "script", Alias: "setup", Source: config.Source{"task setup"}, DependsOn: []string{"task"}},
+		{Type: "script", Alias: "lint", Source: config.Source{"task lint"}, DependsOn: []string{"setup"}},
+		{Type: "npm", Source: config.Source{"prettier"}},
+		{Type: "npm", Source: config.Source{"eslint"}},
+	}
+	plan, err := NewPlan(tools)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !plan.Done() {
+				tool, ok := plan.Next()
+				if !ok {
+					runtime.Gosched()
+					continue
+				}
+				mu.Lock()
+				order = append(order, tool.DisplayName())
+				mu.Unlock()
+				plan.Finish(tool.DisplayName(), nil)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(order) != len(tools) {
+		t.Fatalf("expected every tool to be installed once, got %v", order)
+	}
+	index := func(name string) int { return slices.Index(order, name) }
+	if index("task") > index("setup") || index("setup") > index("lint") {
+		t.Errorf("expected dependencies to be installed first, got %v", order)
+	}
+}
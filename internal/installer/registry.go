@@ -0,0 +1,86 @@
+package installer
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/sebakri/box/internal/logging"
+)
+
+// registryURL returns the mirror URL configured for a tool type with its token
+// applied as user info, or "" if no registry is configured.
+func (m *Manager) registryURL(toolType string) (string, error) {
+	reg, ok := m.Registries[toolType]
+	if !ok || reg.URL == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(reg.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s registry url: %w", toolType, err)
+	}
+	token, err := reg.Token()
+	if err != nil {
+		return "", fmt.Errorf("%s registry: %w", toolType, err)
+	}
+	switch {
+	case token != "" && reg.Username != "":
+		u.User = url.UserPassword(reg.Username, token)
+	case token != "":
+		u.User = url.User(token)
+	}
+	return u.String(), nil
+}
+
+// npmRegistry returns the arguments and environment that point npm to the
+// configured registry. The token is passed through a temporary npmrc that
+// references the token variable, so it is never written to disk. The returned
+// function removes the npmrc again.
+func (m *Manager) npmRegistry() ([]string, []string, func(), error) {
+	reg, ok := m.Registries["npm"]
+	if !ok || reg.URL == "" {
+		return nil, nil, func() {}, nil
+	}
+	args := []string{"--registry", reg.URL}
+	if reg.TokenEnv == "" {
+		return args, nil, func() {}, nil
+	}
+	if _, err := reg.Token(); err != nil {
+		return nil, nil, nil, fmt.Errorf("npm registry: %w", err)
+	}
+
+	u, err := url.Parse(reg.URL)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid npm registry url: %w", err)
+	}
+	scope := "//" + u.Host + strings.TrimSuffix(u.Path, "/") + "/"
+
+	npmrc, err := os.CreateTemp(m.TempDir, "box-npmrc-*")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create npmrc: %w", err)
+	}
+	cleanup := func() { _ = os.Remove(npmrc.Name()) }
+	// npm expands ${VAR} in npmrc files from its environment
+	content := fmt.Sprintf("registry=%s\n%s:_authToken=${%s}\n", reg.URL, scope, reg.TokenEnv)
+	if _, err := npmrc.WriteString(content); err != nil {
+		_ = npmrc.Close()
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("failed to write npmrc: %w", err)
+	}
+	if err := npmrc.Close(); err != nil {
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("failed to write npmrc: %w", err)
+	}
+	return args, []string{"NPM_CONFIG_USERCONFIG=" + npmrc.Name()}, cleanup, nil
+}
+
+// redactArgs hides credentials in URL arguments before they are logged.
+func redactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		redacted[i] = logging.RedactURL(arg)
+	}
+	return redacted
+}
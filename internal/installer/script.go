@@ -17,26 +17,8 @@ type ScriptInstaller struct{}
 func (i *ScriptInstaller) Install(tool config.Tool, m *Manager, sandbox bool) ([]string, error) {
 	m.log("Installing via script: %s", tool.DisplayName())
 
-	boxDir := filepath.Join(m.RootDir, ".box")
-	binDir := filepath.Join(boxDir, "bin")
-
-	env := os.Environ()
-	env = append(env, fmt.Sprintf("BOX_DIR=%s", boxDir))
-	env = append(env, fmt.Sprintf("BOX_BIN_DIR=%s", binDir))
-	env = append(env, fmt.Sprintf("BOX_OS=%s", runtime.GOOS))
-	env = append(env, fmt.Sprintf("BOX_ARCH=%s", runtime.GOARCH))
-	env = append(env, fmt.Sprintf("PATH=%s%s%s", binDir, string(os.PathListSeparator), os.Getenv("PATH")))
-
-	if m.TempDir != "" {
-		env = append(env, fmt.Sprintf("TMPDIR=%s", m.TempDir))
-		env = append(env, fmt.Sprintf("TEMP=%s", m.TempDir))
-		env = append(env, fmt.Sprintf("TMP=%s", m.TempDir))
-	}
-
-	// Add project custom env vars
-	for k, v := range m.Env {
-		env = append(env, fmt.Sprintf("%s=%s", k, v))
-	}
+	binDir := filepath.Join(m.RootDir, ".box", "bin")
+	env := m.scriptEnv()
 
 	if err := m.runCommand("sh", []string{"-c", tool.Source.String()}, env, m.RootDir, sandbox); err != nil {
 		return nil, err
@@ -59,3 +41,35 @@ func (i *ScriptInstaller) Install(tool config.Tool, m *Manager, sandbox bool) ([
 
 	return createdFiles, nil
 }
+
+// scriptEnv returns the environment of install scripts and post-install
+// hooks: the box directories and platform, .box/bin on PATH, the session's
+// temporary directory and the project's env.
+func (m *Manager) scriptEnv() []string {
+	boxDir := filepath.Join(m.RootDir, ".box")
+	binDir := filepath.Join(boxDir, "bin")
+
+	env := os.Environ()
+	env = append(env, fmt.Sprintf("BOX_DIR=%s", boxDir))
+	env = append(env, fmt.Sprintf("BOX_BIN_DIR=%s", binDir))
+	env = append(env, fmt.Sprintf("BOX_OS=%s", runtime.GOOS))
+	env = append(env, fmt.Sprintf("BOX_ARCH=%s", runtime.GOARCH))
+	env = append(env, fmt.Sprintf("BOX_CACHE_DIR=%s", m.cacheDir()))
+	if m.Offline {
+		// Scripts cannot be fetched, but can check this to use cached downloads
+		env = append(env, "BOX_OFFLINE=1")
+	}
+	env = append(env, fmt.Sprintf("PATH=%s%s%s", binDir, string(os.PathListSeparator), os.Getenv("PATH")))
+
+	if m.TempDir != "" {
+		env = append(env, fmt.Sprintf("TMPDIR=%s", m.TempDir))
+		env = append(env, fmt.Sprintf("TEMP=%s", m.TempDir))
+		env = append(env, fmt.Sprintf("TMP=%s", m.TempDir))
+	}
+
+	// Add project custom env vars
+	for k, v := range m.Env {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	return env
+}
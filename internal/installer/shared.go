@@ -0,0 +1,106 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/store"
+)
+
+// installShared installs the tool into the shared store, unless an entry for
+// it already exists, and links its binaries into the project's .box/bin.
+// before is the state of the project's .box directory before the install.
+func (m *Manager) installShared(tool config.Tool, before map[string]time.Time) error {
+	dir, complete, release, err := m.Store.Acquire(tool)
+	if err != nil {
+		return err
+	}
+
+	if complete {
+		m.log("Using %s from the shared store (%s)", tool.DisplayName(), dir)
+	} else {
+		m.log("Installing %s into the shared store (%s)...", tool.DisplayName(), dir)
+		// The store entry is laid out like a project, so the installers work unchanged
+		shared := *m
+		shared.CacheDir = m.cacheDir()
+		// Entries share one module cache instead of downloading into their own GOPATH
+		shared.PackageCache = m.Store.CacheDir()
+		shared.RootDir = dir
+		shared.Store = nil
+		// Hooks run and binaries are renamed in the project, not in the store entry
+		bare := unexposed(tool)
+		bare.PostInstall, bare.Verify, bare.VersionCommand = nil, nil, ""
+		installErr := shared.install(bare)
+		if err := release(installErr == nil); err != nil && installErr == nil {
+			installErr = err
+		}
+		if installErr != nil {
+			return installErr
+		}
+	}
+
+	// Absolute links keep working if the project is moved
+	files, err := m.linkEntry(tool, dir, true)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Store.Register(m.RootDir); err != nil {
+		m.warn("Failed to register project with the shared store: %v", err)
+	}
+	if err := m.runHooks(tool); err != nil {
+		m.removeNewFiles(before)
+		return err
+	}
+	return m.updateManifest(tool, files, m.sharedVersion(tool, dir), filepath.Base(dir), nil)
+}
+
+// storeBinaries returns the names of the binaries linked into .box/bin of a store entry.
+func storeBinaries(dir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, ".box", "bin"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read binaries of store entry %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || (runtime.GOOS == "windows" && !strings.HasSuffix(e.Name(), ".exe")) {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// UseSharedStore reports whether the shared store is enabled for cfg. The
+// BOX_SHARED_STORE environment variable takes precedence over box.yml.
+func UseSharedStore(cfg *config.Config) bool {
+	switch strings.ToLower(os.Getenv(store.EnvVar)) {
+	case "1", "true", "yes":
+		return true
+	case "0", "false", "no":
+		return false
+	}
+	return cfg.SharedStore
+}
+
+// sharedVersion returns the installed version of a tool in the store entry
+// at dir. A version_command runs in the project, like the other hooks.
+func (m *Manager) sharedVersion(tool config.Tool, dir string) string {
+	if tool.VersionCommand != "" {
+		return m.resolveVersion(tool, nil, m)
+	}
+	entry := &Manager{RootDir: dir}
+	manifest, err := entry.LoadManifest()
+	if err != nil {
+		return ""
+	}
+	return manifest.Tools[tool.DisplayName()].ResolvedVersion
+}
@@ -0,0 +1,185 @@
+package installer
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/store"
+)
+
+// fakeInstaller writes a binary into .box/fake/bin and links it, counting its
+// invocations and recording the package cache it was given.
+type fakeInstaller struct {
+	calls        int
+	packageCache string
+}
+
+func (f *fakeInstaller) Install(tool config.Tool, m *Manager, _ bool) ([]string, error) {
+	f.calls++
+	f.packageCache = m.PackageCache
+	fakeBinDir := filepath.Join(m.RootDir, ".box", "fake", "bin")
+	if err := os.MkdirAll(fakeBinDir, 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(fakeBinDir, tool.Source.String()), []byte("binary"), 0600); err != nil {
+		return nil, err
+	}
+	return m.linkBinaries(tool, fakeBinDir, filepath.Join(m.RootDir, ".box", "bin"), []string{tool.Source.String()})
+}
+
+func TestInstallShared(t *testing.T) {
+	s := &store.Store{Dir: filepath.Join(t.TempDir(), "store")}
+	fake := &fakeInstaller{}
+	tool := config.Tool{Type: "fake", Source: config.Source{"hello"}, Version: "1.0.0"}
+
+	projects := []string{t.TempDir(), t.TempDir()}
+	for _, root := range projects {
+		m := New(root, t.TempDir(), nil, &config.Config{})
+		m.Output = io.Discard
+		m.Store = s
+		m.RegisterInstaller("fake", fake)
+
+		if err := m.Install(tool); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(root, ".box", "bin", "hello"))
+		if err != nil || string(data) != "binary" {
+			t.Errorf("expected .box/bin/hello to resolve into the store, got %q (%v)", data, err)
+		}
+		if _, err := os.Stat(filepath.Join(root, ".box", "fake")); !os.IsNotExist(err) {
+			t.Errorf("expected no project-local install in %s", root)
+		}
+
+		manifest, err := m.LoadManifest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if manifest.Tools["hello"].Store != store.Key(tool) {
+			t.Errorf("expected manifest to reference store key %s, got %+v", store.Key(tool), manifest.Tools["hello"])
+		}
+	}
+
+	if fake.calls != 1 {
+		t.Errorf("expected the tool to be installed once, got %d installs", fake.calls)
+	}
+	if fake.packageCache != s.CacheDir() {
+		t.Errorf("expected the store entry to use the package cache %s, got %q", s.CacheDir(), fake.packageCache)
+	}
+
+	entries, err := s.Entries()
+	if err != nil || len(entries) != 1 || entries[0].Source != "hello" {
+		t.Fatalf("unexpected store entries: %+v (%v)", entries, err)
+	}
+
+	// Entries stay while any registered project references them
+	if err := os.RemoveAll(filepath.Join(projects[0], ".box")); err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := s.GC(false); err != nil || len(removed) != 0 {
+		t.Errorf("expected nothing to be collected, got %+v (%v)", removed, err)
+	}
+
+	if err := os.RemoveAll(filepath.Join(projects[1], ".box")); err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := s.GC(false); err != nil || len(removed) != 1 {
+		t.Errorf("expected the entry to be collected, got %+v (%v)", removed, err)
+	}
+	if _, err := os.Stat(s.Path(store.Key(tool))); !os.IsNotExist(err) {
+		t.Error("expected store entry to be removed")
+	}
+	if projects, _ := s.Projects(); len(projects) != 0 {
+		t.Errorf("expected projects without manifest to be unregistered, got %v", projects)
+	}
+}
+
+func TestInstallSharedFloatingVersion(t *testing.T) {
+	for _, version := range []string{"", "latest", "^1.2"} {
+		t.Run(version, func(t *testing.T) {
+			root := t.TempDir()
+			s := &store.Store{Dir: filepath.Join(t.TempDir(), "store")}
+			m := New(root, t.TempDir(), nil, &config.Config{})
+			m.Output = io.Discard
+			m.Store = s
+			m.RegisterInstaller("fake", &fakeInstaller{})
+
+			tool := config.Tool{Type: "fake", Source: config.Source{"hello"}, Version: version}
+			if err := m.Install(tool); err != nil {
+				t.Fatalf("Install failed: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(root, ".box", "fake", "bin", "hello")); err != nil {
+				t.Errorf("expected a project-local install: %v", err)
+			}
+			if entries, err := s.Entries(); err != nil || len(entries) != 0 {
+				t.Errorf("expected no store entries, got %+v (%v)", entries, err)
+			}
+		})
+	}
+}
+
+func TestInstallSharedFailure(t *testing.T) {
+	s := &store.Store{Dir: filepath.Join(t.TempDir(), "store")}
+	m := New(t.TempDir(), t.TempDir(), nil, &config.Config{})
+	m.Output = io.Discard
+	m.Store = s
+
+	tool := config.Tool{Type: "npm", Source: config.Source{"does-not-exist"}, Version: "1.0.0", Binaries: []string{"nothing"}}
+	m.RegisterInstaller("npm", &failingInstaller{})
+	if err := m.Install(tool); err == nil {
+		t.Fatal("expected install to fail")
+	}
+	if _, err := os.Stat(s.Path(store.Key(tool))); !os.IsNotExist(err) {
+		t.Error("expected failed install to leave no store entry")
+	}
+	if _, err := os.Stat(s.Path(store.Key(tool)) + ".lock"); !os.IsNotExist(err) {
+		t.Error("expected lock to be released")
+	}
+}
+
+type failingInstaller struct{}
+
+func (failingInstaller) Install(config.Tool, *Manager, bool) ([]string, error) {
+	return nil, os.ErrNotExist
+}
+
+func TestUseSharedStore(t *testing.T) {
+	cfg := &config.Config{SharedStore: true}
+	t.Setenv(store.EnvVar, "")
+	if !UseSharedStore(cfg) || UseSharedStore(&config.Config{}) {
+		t.Error("expected box.yml setting to be used")
+	}
+	t.Setenv(store.EnvVar, "0")
+	if UseSharedStore(cfg) {
+		t.Errorf("expected %s=0 to disable the store", store.EnvVar)
+	}
+	t.Setenv(store.EnvVar, "1")
+	if !UseSharedStore(&config.Config{}) {
+		t.Errorf("expected %s=1 to enable the store", store.EnvVar)
+	}
+}
+
+func TestGoEnvPackageCache(t *testing.T) {
+	m := New(t.TempDir(), "", nil, &config.Config{})
+	env, err := m.goEnv(filepath.Join(m.RootDir, ".box", "go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.ContainsFunc(env, func(e string) bool { return strings.HasPrefix(e, "GOMODCACHE=") }) {
+		t.Errorf("expected the module cache in GOPATH without a package cache, got %v", env)
+	}
+
+	m.PackageCache = filepath.Join(t.TempDir(), "cache")
+	env, err = m.goEnv(filepath.Join(m.RootDir, ".box", "go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "GOMODCACHE=" + filepath.Join(m.PackageCache, "go"); !slices.Contains(env, want) {
+		t.Errorf("expected %s, got %v", want, env)
+	}
+}
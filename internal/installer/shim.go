@@ -0,0 +1,86 @@
+package installer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// shimMarker precedes the path of the real binary in a shim, relative to the
+// directory of the shim unless it is absolute.
+const shimMarker = "box-shim-target: "
+
+// shimExt is the file extension of shims.
+var shimExt = func() string {
+	if runtime.GOOS == "windows" {
+		return ".cmd"
+	}
+	return ""
+}()
+
+// writeShim writes a shim to dest that runs the binary at target with
+// 'box run', which sets up the environment and sandbox of the project. The
+// BOX environment variable selects the box executable, default "box".
+func (m *Manager) writeShim(dest, target string) error {
+	name := filepath.Base(dest)
+	config := m.configFileName()
+
+	var content string
+	if runtime.GOOS == "windows" {
+		content = fmt.Sprintf("@echo off\r\n"+
+			"rem Generated by box. Runs %s with the environment and sandbox of the project.\r\n"+
+			"rem %s%s\r\n"+
+			"setlocal\r\n"+
+			"if not defined BOX set \"BOX=box\"\r\n"+
+			"\"%%BOX%%\" -f \"%%~dp0..\\..\\%s\" run \"%s\" %%*\r\n",
+			name, shimMarker, target, config, name)
+	} else {
+		content = fmt.Sprintf("#!/bin/sh\n"+
+			"# Generated by box. Runs %s with the environment and sandbox of the project.\n"+
+			"# %s%s\n"+
+			"dir=$(CDPATH= cd -- \"$(dirname -- \"$0\")\" && pwd)\n"+
+			"exec \"${BOX:-box}\" -f \"$dir/../../%s\" run '%s' \"$@\"\n",
+			name, shimMarker, filepath.ToSlash(target), config, strings.ReplaceAll(name, "'", `'\''`))
+	}
+
+	// dest may be a link to the binary, which must not be overwritten
+	_ = os.Remove(dest)
+	//nolint:gosec
+	if err := os.WriteFile(dest, []byte(content), 0700); err != nil {
+		return fmt.Errorf("failed to write shim %s: %w", dest, err)
+	}
+	return nil
+}
+
+// ShimTarget returns the path of the binary run by the shim at path, or ""
+// if path is not a shim.
+func ShimTarget(path string) string {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	// The marker is in the first lines, so binaries are not read completely
+	scanner := bufio.NewScanner(io.LimitReader(f, 1024))
+	for i := 0; i < 3 && scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+		target, ok := strings.CutPrefix(line, "# "+shimMarker)
+		if !ok {
+			target, ok = strings.CutPrefix(line, "rem "+shimMarker)
+		}
+		if !ok {
+			continue
+		}
+		target = filepath.FromSlash(target)
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+		return target
+	}
+	return ""
+}
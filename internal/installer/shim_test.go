@@ -0,0 +1,139 @@
+package installer
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sebakri/box/internal/config"
+	"github.com/sebakri/box/internal/store"
+)
+
+func TestInstallShims(t *testing.T) {
+	tool := config.Tool{Type: "fake", Source: config.Source{"hello"}, Version: "1.0.0", Rename: map[string]string{"hello": "hi"}}
+
+	tests := []struct {
+		name   string
+		shared bool
+	}{
+		{"project", false},
+		{"shared store", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			m := New(root, t.TempDir(), nil, &config.Config{Shims: true, Tools: []config.Tool{tool}})
+			m.Output = io.Discard
+			m.RegisterInstaller("fake", &fakeInstaller{})
+			if tt.shared {
+				m.Store = &store.Store{Dir: filepath.Join(t.TempDir(), "store")}
+			}
+
+			if err := m.Install(tool); err != nil {
+				t.Fatalf("Install failed: %v", err)
+			}
+
+			shim := filepath.Join(root, ".box", "bin", "hi"+shimExt)
+			info, err := os.Lstat(shim)
+			if err != nil || !info.Mode().IsRegular() {
+				t.Fatalf("expected a shim at %s, got %v (%v)", shim, info, err)
+			}
+			target := ShimTarget(shim)
+			data, err := os.ReadFile(target)
+			if err != nil || string(data) != "binary" {
+				t.Errorf("expected the shim to point to the binary, got %q: %q (%v)", target, data, err)
+			}
+			if _, err := os.Lstat(filepath.Join(root, ".box", "bin", "hello")); !os.IsNotExist(err) {
+				t.Error("expected no link under the original name")
+			}
+
+			statuses, err := m.Status(m.GlobalConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(statuses) != 1 || len(statuses[0].Changes) != 0 {
+				t.Errorf("expected the shimmed tool to be installed as configured, got %+v", statuses)
+			}
+		})
+	}
+}
+
+func TestInstallShimsWorkspaceMember(t *testing.T) {
+	tool := config.Tool{Type: "fake", Source: config.Source{"hello"}}
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "box.yaml"), "workspace:\n  members: [app]\n")
+	member := filepath.Join(root, "app", ".box.yml")
+	writeTestFile(t, member, "shims: true\n")
+
+	// Members install into the .box directory of the workspace root
+	m := New(root, t.TempDir(), nil, &config.Config{Shims: true, Tools: []config.Tool{tool}})
+	m.ConfigFile = member
+	m.Output = io.Discard
+	m.RegisterInstaller("fake", &fakeInstaller{})
+	if err := m.Install(tool); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, ".box", "bin", "hello"+shimExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "box.yaml") || strings.Contains(string(data), ".box.yml") {
+		t.Errorf("expected the shim to use the configuration of the workspace root, got:\n%s", data)
+	}
+}
+
+func TestShimTarget(t *testing.T) {
+	dir := t.TempDir()
+	m := New(dir, t.TempDir(), nil, &config.Config{})
+	m.Output = io.Discard
+
+	shim := filepath.Join(dir, "shim")
+	if err := m.writeShim(shim, filepath.Join("..", "go", "bin", "tool")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ShimTarget(shim), filepath.Join(dir, "..", "go", "bin", "tool"); got != want {
+		t.Errorf("expected target %q, got %q", want, got)
+	}
+
+	binary := filepath.Join(dir, "binary")
+	if err := os.WriteFile(binary, []byte("\x7fELF # box-shim-target: nope"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{binary, filepath.Join(dir, "missing")} {
+		if got := ShimTarget(path); got != "" {
+			t.Errorf("expected no target for %s, got %q", path, got)
+		}
+	}
+}
+
+func TestCopyExecutable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissions are not preserved on Windows")
+	}
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	//nolint:gosec
+	if err := os.WriteFile(src, []byte("binary"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(src, 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(dir, "dest")
+	if err := copyExecutable(src, dest); err != nil {
+		t.Fatalf("copyExecutable failed: %v", err)
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0750 {
+		t.Errorf("expected mode 0750, got %v", info.Mode().Perm())
+	}
+}
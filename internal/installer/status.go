@@ -0,0 +1,147 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// State is the installation state of a tool compared to its configuration.
+type State string
+
+// Installation states reported by Status.
+const (
+	StateInstalled    State = "installed"
+	StateNotInstalled State = "not installed"
+	StateChanged      State = "changed"     // Installed with a different definition
+	StateOrphaned     State = "orphaned"    // Installed but no longer configured
+	StateBrokenLink   State = "broken link" // A binary in .box/bin points to a missing file
+)
+
+// ToolStatus describes the state of a configured or installed tool.
+type ToolStatus struct {
+	Name            string   `json:"name"`
+	Type            string   `json:"type"`
+	State           State    `json:"state"`
+	ResolvedVersion string   `json:"resolved_version,omitempty"` // Version that was actually installed
+	Changes         []string `json:"changes,omitempty"`          // Differences between configuration and manifest
+	Broken          []string `json:"broken,omitempty"`           // Binaries whose link target is missing
+}
+
+// Status compares the tools of cfg with the manifest. Tools in the manifest
+// that are not part of cfg are reported as orphaned after the configured ones.
+func (m *Manager) Status(cfg *config.Config) ([]ToolStatus, error) {
+	manifest, err := m.LoadManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]ToolStatus, 0, len(cfg.Tools))
+	configured := make(map[string]bool, len(cfg.Tools))
+	for _, tool := range cfg.Tools {
+		name := tool.DisplayName()
+		configured[name] = true
+
+		status := ToolStatus{Name: name, Type: tool.Type, State: StateInstalled}
+		info, ok := manifest.Tools[name]
+		if !ok {
+			status.State = StateNotInstalled
+			statuses = append(statuses, status)
+			continue
+		}
+
+		status.ResolvedVersion = info.ResolvedVersion
+		status.Changes = m.changes(tool, info)
+		status.Broken = m.brokenBinaries(info)
+		switch {
+		case len(status.Changes) > 0:
+			status.State = StateChanged
+		case len(status.Broken) > 0:
+			status.State = StateBrokenLink
+		}
+		statuses = append(statuses, status)
+	}
+
+	var orphans []string
+	for name := range manifest.Tools {
+		if !configured[name] {
+			orphans = append(orphans, name)
+		}
+	}
+	sort.Strings(orphans)
+	for _, name := range orphans {
+		statuses = append(statuses, ToolStatus{Name: name, Type: manifest.Tools[name].Type, State: StateOrphaned})
+	}
+	return statuses, nil
+}
+
+// changes lists how the installed tool differs from its configuration.
+func (m *Manager) changes(tool config.Tool, info ToolManifest) []string {
+	var changes []string
+	diff := func(field, installed, configured string) {
+		if installed != configured {
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", field, orNone(installed), orNone(configured)))
+		}
+	}
+	diff("type", info.Type, tool.Type)
+	diff("source", info.Source, tool.Source.String())
+	diff("version", info.Version, tool.Version)
+	// An exact version must also be the one that was installed
+	if info.Version == tool.Version && exactVersion.MatchString(tool.Version) && info.ResolvedVersion != "" &&
+		strings.TrimPrefix(info.ResolvedVersion, "v") != strings.TrimPrefix(tool.Version, "v") {
+		changes = append(changes, fmt.Sprintf("version: %s was installed as %s", tool.Version, info.ResolvedVersion))
+	}
+	diff("args", strings.Join(info.Args, " "), strings.Join(tool.Args, " "))
+
+	installed := installedBinaries(info)
+	for _, name := range tool.LinkNames() {
+		if !slices.Contains(installed, name) && !slices.Contains(installed, name+".exe") && !slices.Contains(installed, name+shimExt) {
+			changes = append(changes, fmt.Sprintf("binaries: %s is not installed", name))
+		}
+	}
+	return changes
+}
+
+// brokenBinaries returns the binaries of the tool in .box/bin that do not
+// resolve to an existing file, including shims whose binary is missing.
+func (m *Manager) brokenBinaries(info ToolManifest) []string {
+	var broken []string
+	for _, name := range installedBinaries(info) {
+		path := filepath.Join(m.RootDir, ".box", "bin", name)
+		if target := ShimTarget(path); target != "" {
+			path = target
+		}
+		if _, err := os.Stat(path); err != nil {
+			broken = append(broken, name)
+		}
+	}
+	return broken
+}
+
+// installedBinaries returns the names of the files the tool installed into .box/bin.
+func installedBinaries(info ToolManifest) []string {
+	var binaries []string
+	binDir := filepath.Join(".box", "bin")
+	for _, file := range info.Files {
+		if filepath.Dir(file) == binDir {
+			binaries = append(binaries, filepath.Base(file))
+		}
+	}
+	return binaries
+}
+
+// exactVersion matches versions that name a single release, unlike "latest" or ranges.
+var exactVersion = regexp.MustCompile(`^v?\d+(\.\d+)+([-+][0-9A-Za-z.+-]+)?$`)
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
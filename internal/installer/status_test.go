@@ -0,0 +1,97 @@
+package installer
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+func TestStatus(t *testing.T) {
+	root := t.TempDir()
+	m := New(root, t.TempDir(), nil, &config.Config{})
+	m.Output = io.Discard
+	m.RegisterInstaller("fake", &fakeInstaller{})
+
+	installed := []config.Tool{
+		{Type: "fake", Source: config.Source{"hello"}, Version: "1.0.0"},
+		{Type: "fake", Source: config.Source{"world"}},
+		{Type: "fake", Source: config.Source{"broken"}},
+		{Type: "fake", Source: config.Source{"orphan"}},
+	}
+	for _, tool := range installed {
+		if err := m.Install(tool); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+	}
+	if err := os.Remove(filepath.Join(root, ".box", "fake", "bin", "broken")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Tools: []config.Tool{
+		{Type: "fake", Source: config.Source{"hello"}, Version: "2.0.0"},
+		{Type: "fake", Source: config.Source{"world"}},
+		{Type: "fake", Source: config.Source{"broken"}},
+		{Type: "fake", Source: config.Source{"missing"}},
+	}}
+	statuses, err := m.Status(cfg)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+
+	expected := []struct {
+		name  string
+		state State
+	}{
+		{"hello", StateChanged},
+		{"world", StateInstalled},
+		{"broken", StateBrokenLink},
+		{"missing", StateNotInstalled},
+		{"orphan", StateOrphaned},
+	}
+	if len(statuses) != len(expected) {
+		t.Fatalf("expected %d statuses, got %+v", len(expected), statuses)
+	}
+	for i, want := range expected {
+		if statuses[i].Name != want.name || statuses[i].State != want.state {
+			t.Errorf("status %d: expected %s %s, got %+v", i, want.name, want.state, statuses[i])
+		}
+	}
+	if len(statuses[0].Changes) != 1 || statuses[0].Changes[0] != "version: 1.0.0 → 2.0.0" {
+		t.Errorf("expected a version change, got %v", statuses[0].Changes)
+	}
+}
+
+func TestStatusResolvedVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("version commands run with sh")
+	}
+
+	m := New(t.TempDir(), t.TempDir(), nil, &config.Config{})
+	m.Output = io.Discard
+	m.RegisterInstaller("fake", &fakeInstaller{})
+
+	tools := []config.Tool{
+		{Type: "fake", Source: config.Source{"pinned"}, Version: "v1.2.0", VersionCommand: "echo v1.3.0"},
+		{Type: "fake", Source: config.Source{"latest"}, Version: "latest", VersionCommand: "echo v1.3.0"},
+	}
+	for _, tool := range tools {
+		if err := m.Install(tool); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+	}
+
+	statuses, err := m.Status(&config.Config{Tools: tools})
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if statuses[0].State != StateChanged || len(statuses[0].Changes) != 1 || statuses[0].Changes[0] != "version: v1.2.0 was installed as v1.3.0" {
+		t.Errorf("expected the pinned tool to be changed, got %+v", statuses[0])
+	}
+	if statuses[1].State != StateInstalled || statuses[1].ResolvedVersion != "v1.3.0" {
+		t.Errorf("expected latest to be installed as v1.3.0, got %+v", statuses[1])
+	}
+}
@@ -27,12 +27,24 @@ func (i *UvInstaller) Install(tool config.Tool, m *Manager, sandbox bool) ([]str
 	// uv tool install --force <package>
 	// UV_TOOL_BIN_DIR and UV_TOOL_DIR ensure project-local installation
 	args := []string{"tool", "install", "--force"}
+	if m.Offline {
+		args = append(args, "--offline")
+	}
 	args = append(args, tool.Args...)
 	args = append(args, source)
 
 	env := os.Environ()
 	env = append(env, fmt.Sprintf("UV_TOOL_BIN_DIR=%s", uvBinDir))
 	env = append(env, fmt.Sprintf("UV_TOOL_DIR=%s", uvDir))
+	if m.Offline {
+		env = append(env, fmt.Sprintf("UV_CACHE_DIR=%s", m.cachePath("uv")))
+	} else if cache := m.packageCachePath("uv"); cache != "" {
+		env = append(env, fmt.Sprintf("UV_CACHE_DIR=%s", cache))
+	}
+	env, err := m.uvRegistryEnv(env)
+	if err != nil {
+		return nil, err
+	}
 
 	if err := m.runCommand("uv", args, env, "", sandbox); err != nil {
 		return nil, err
@@ -43,5 +55,47 @@ func (i *UvInstaller) Install(tool config.Tool, m *Manager, sandbox bool) ([]str
 		binaries = []string{m.detectBinaryName(source)}
 	}
 
-	return m.linkBinaries(uvBinDir, binDir, binaries)
+	return m.linkBinaries(tool, uvBinDir, binDir, binaries)
+}
+
+// Fetch downloads the wheels of a Python tool into the uv cache in
+// .box/cache/uv by installing it into a temporary tool directory.
+func (i *UvInstaller) Fetch(tool config.Tool, m *Manager, sandbox bool) error {
+	source := tool.Source.String()
+	if tool.Version != "" {
+		source = fmt.Sprintf("%s==%s", source, tool.Version)
+	}
+	m.log("Fetching %s (uv)...", tool.DisplayName())
+
+	scratch, cleanup, err := m.scratchDir()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	args := []string{"tool", "install", "--force"}
+	args = append(args, tool.Args...)
+	args = append(args, source)
+
+	env := os.Environ()
+	env = append(env, fmt.Sprintf("UV_TOOL_BIN_DIR=%s", filepath.Join(scratch, "bin")))
+	env = append(env, fmt.Sprintf("UV_TOOL_DIR=%s", scratch))
+	env = append(env, fmt.Sprintf("UV_CACHE_DIR=%s", m.cachePath("uv")))
+	if env, err = m.uvRegistryEnv(env); err != nil {
+		return err
+	}
+
+	return m.runCommand("uv", args, env, "", sandbox)
+}
+
+// uvRegistryEnv adds the configured package index to env.
+func (m *Manager) uvRegistryEnv(env []string) ([]string, error) {
+	index, err := m.registryURL("uv")
+	if err != nil {
+		return nil, err
+	}
+	if index != "" {
+		env = append(env, fmt.Sprintf("UV_INDEX_URL=%s", index))
+	}
+	return env, nil
 }
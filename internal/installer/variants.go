@@ -0,0 +1,132 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// variantDir returns the directory a tool is installed into if it needs one
+// of its own, see installVariant.
+func (m *Manager) variantDir(tool config.Tool) string {
+	return filepath.Join(m.RootDir, ".box", "variants", unsafeLogChars.ReplaceAllString(tool.DisplayName(), "_"))
+}
+
+// installVariant installs a tool into its own directory and links its
+// binaries into .box/bin under their link names. This keeps several versions
+// of a tool with a bin_name_template apart, and lets box rename the binaries
+// of scripts, which write to .box/bin themselves.
+func (m *Manager) installVariant(tool config.Tool, installer Installer, sandboxEnabled bool) ([]string, *Manager, error) {
+	dir := m.variantDir(tool)
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, nil, fmt.Errorf("failed to clean variant directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".box", "bin"), 0700); err != nil {
+		return nil, nil, fmt.Errorf("failed to create variant directory: %w", err)
+	}
+
+	// The variant directory is laid out like a project, so the installers work unchanged
+	variant := *m
+	variant.CacheDir = m.cacheDir()
+	variant.RootDir = dir
+	variant.Store = nil
+	if _, err := installer.Install(unexposed(tool), &variant, sandboxEnabled); err != nil {
+		return nil, nil, err
+	}
+
+	// Relative links keep working if the project is moved
+	files, err := m.linkEntry(tool, dir, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return files, &variant, nil
+}
+
+// linkEntry links the binaries in .box/bin of dir, a store entry or variant
+// directory, into the .box/bin of the project under the tool's link names.
+func (m *Manager) linkEntry(tool config.Tool, dir string, absolute bool) ([]string, error) {
+	binaries, err := storeBinaries(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	binDir := filepath.Join(m.RootDir, ".box", "bin")
+	files := make([]string, 0, len(binaries))
+	for _, name := range binaries {
+		file, err := m.linkBinary(tool, name, filepath.Join(dir, ".box", "bin", name), binDir, absolute)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// needsVariant reports whether the tool is installed with installVariant.
+func needsVariant(tool config.Tool) bool {
+	return tool.BinNameTemplate != "" || (tool.Type == "script" && (len(tool.Rename) > 0 || tool.Shim))
+}
+
+// unexposed returns the tool with its binaries under their own names and
+// without shims, for installs into a store entry or variant directory whose
+// binaries are linked into the project afterwards.
+func unexposed(tool config.Tool) config.Tool {
+	tool.BinNameTemplate, tool.Rename, tool.Shim = "", nil, false
+	return tool
+}
+
+// checkBinaryConflicts fails if a binary of the tool is already installed by
+// another configured tool. Binaries of tools that are no longer configured
+// are replaced with a warning.
+func (m *Manager) checkBinaryConflicts(tool config.Tool) error {
+	names := tool.LinkNames()
+	if len(names) == 0 {
+		return nil
+	}
+	manifest, err := m.LoadManifest()
+	if err != nil {
+		return err
+	}
+
+	owners := make([]string, 0, len(manifest.Tools))
+	for owner := range manifest.Tools {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	for _, owner := range owners {
+		if owner == tool.DisplayName() {
+			continue
+		}
+		for _, bin := range installedBinaries(manifest.Tools[owner]) {
+			if !slices.Contains(names, bin) && !slices.Contains(names, strings.TrimSuffix(bin, ".exe")) {
+				continue
+			}
+			if !m.isConfigured(owner) {
+				m.warn("Replacing binary %s of %s, which is no longer configured", bin, owner)
+				continue
+			}
+			return fmt.Errorf("binary %s is already installed by %s; use bin_name_template or binaries to install both", bin, owner)
+		}
+	}
+	return nil
+}
+
+// isConfigured reports whether a tool is in the configuration. Without a
+// configuration, every tool is considered configured.
+func (m *Manager) isConfigured(name string) bool {
+	if m.GlobalConfig == nil {
+		return true
+	}
+	for _, t := range m.GlobalConfig.Tools {
+		if t.DisplayName() == name {
+			return true
+		}
+	}
+	return false
+}
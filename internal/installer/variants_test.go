@@ -0,0 +1,83 @@
+package installer
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+func TestInstallVariants(t *testing.T) {
+	root := t.TempDir()
+	v1 := config.Tool{Type: "fake", Source: config.Source{"hello"}, Version: "1.0.0", BinNameTemplate: "{{.Name}}@{{.Version}}"}
+	v2 := v1
+	v2.Version = "2.0.0"
+
+	m := New(root, t.TempDir(), nil, &config.Config{Tools: []config.Tool{v1, v2}})
+	m.Output = io.Discard
+	m.RegisterInstaller("fake", &fakeInstaller{})
+
+	for _, tool := range []config.Tool{v1, v2} {
+		if err := m.Install(tool); err != nil {
+			t.Fatalf("Install of %s failed: %v", tool.DisplayName(), err)
+		}
+	}
+	for _, name := range []string{"hello@1.0.0", "hello@2.0.0"} {
+		data, err := os.ReadFile(filepath.Join(root, ".box", "bin", name))
+		if err != nil || string(data) != "binary" {
+			t.Errorf("expected .box/bin/%s to resolve to the binary, got %q (%v)", name, data, err)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(root, ".box", "bin", "hello")); !os.IsNotExist(err) {
+		t.Error("expected no untemplated binary in .box/bin")
+	}
+
+	if err := m.Uninstall(v1.DisplayName()); err != nil {
+		t.Fatalf("Uninstall failed: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(root, ".box", "bin", "hello@1.0.0")); !os.IsNotExist(err) {
+		t.Error("expected the binary of the uninstalled version to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(root, ".box", "bin", "hello@2.0.0")); err != nil {
+		t.Errorf("expected the other version to remain installed: %v", err)
+	}
+}
+
+func TestInstallBinaryConflict(t *testing.T) {
+	first := config.Tool{Type: "fake", Source: config.Source{"hello"}, Version: "1.0.0"}
+	second := config.Tool{Type: "fake", Source: config.Source{"hello"}, Version: "2.0.0", Alias: "hello2"}
+
+	tests := []struct {
+		name       string
+		configured []config.Tool
+		wantErr    string
+	}{
+		{"configured owner", []config.Tool{first, second}, "binary hello is already installed by hello"},
+		{"orphaned owner", []config.Tool{second}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(t.TempDir(), t.TempDir(), nil, &config.Config{})
+			m.Output = io.Discard
+			m.RegisterInstaller("fake", &fakeInstaller{})
+			if err := m.Install(first); err != nil {
+				t.Fatalf("Install failed: %v", err)
+			}
+
+			m.GlobalConfig = &config.Config{Tools: tt.configured}
+			err := m.Install(second)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected the binary of an unconfigured tool to be replaced, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package installer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FileRecord describes an installed file at the time it was recorded in the manifest.
+type FileRecord struct {
+	SHA256 string      `json:"sha256,omitempty"`
+	Size   int64       `json:"size,omitempty"`
+	Mode   os.FileMode `json:"mode"`
+	Link   string      `json:"link,omitempty"` // Target of a symlink
+}
+
+// recordFile records the file at path without following symlinks.
+func recordFile(path string) (FileRecord, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return FileRecord{}, err
+	}
+
+	record := FileRecord{Mode: info.Mode()}
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		record.Link, err = os.Readlink(path)
+		return record, err
+	case info.IsDir():
+		return record, nil
+	}
+
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return FileRecord{}, err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return FileRecord{}, err
+	}
+	record.SHA256 = hex.EncodeToString(h.Sum(nil))
+	record.Size = info.Size()
+	return record, nil
+}
+
+// recordFiles records the given files relative to RootDir. Files that no
+// longer exist are left out.
+func (m *Manager) recordFiles(files []string) map[string]FileRecord {
+	records := make(map[string]FileRecord, len(files))
+	for _, file := range files {
+		if record, err := recordFile(filepath.Join(m.RootDir, file)); err == nil {
+			records[file] = record
+		}
+	}
+	return records
+}
+
+// ToolReport lists the files of an installed tool that differ from the manifest.
+type ToolReport struct {
+	Name       string   `json:"name"`
+	Modified   []string `json:"modified,omitempty"`
+	Missing    []string `json:"missing,omitempty"`
+	Unverified bool     `json:"unverified,omitempty"` // Installed before integrity records were kept, only missing files are detected
+}
+
+// VerifyReport is the result of Verify.
+type VerifyReport struct {
+	Tools      []ToolReport `json:"tools"`                // Tools with modified or missing files
+	Unexpected []string     `json:"unexpected,omitempty"` // Files in .box/bin that no tool installed
+}
+
+// Clean reports whether the installation matches the manifest.
+func (r *VerifyReport) Clean() bool {
+	return len(r.Tools) == 0 && len(r.Unexpected) == 0
+}
+
+// Verify compares the installed files with the records in the manifest.
+func (m *Manager) Verify() (*VerifyReport, error) {
+	manifest, err := m.LoadManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &VerifyReport{Tools: []ToolReport{}}
+	known := make(map[string]bool)
+
+	names := make([]string, 0, len(manifest.Tools))
+	for name := range manifest.Tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		info := manifest.Tools[name]
+		tool := ToolReport{Name: name, Unverified: info.Integrity == nil}
+		for _, file := range info.Files {
+			known[file] = true
+			current, err := recordFile(filepath.Join(m.RootDir, file))
+			if err != nil {
+				tool.Missing = append(tool.Missing, file)
+				continue
+			}
+			recorded, ok := info.Integrity[file]
+			if ok && !sameRecord(recorded, current) {
+				tool.Modified = append(tool.Modified, file)
+			}
+		}
+		if len(tool.Modified) > 0 || len(tool.Missing) > 0 {
+			report.Tools = append(report.Tools, tool)
+		}
+	}
+
+	binDir := filepath.Join(m.RootDir, ".box", "bin")
+	entries, err := os.ReadDir(binDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", binDir, err)
+	}
+	for _, e := range entries {
+		file := filepath.Join(".box", "bin", e.Name())
+		if !known[file] {
+			report.Unexpected = append(report.Unexpected, file)
+		}
+	}
+	return report, nil
+}
+
+// sameRecord compares two records. Only the permission bits of directories
+// are compared, as their size and contents change with every install.
+func sameRecord(recorded, current FileRecord) bool {
+	if recorded.Mode.IsDir() || current.Mode.IsDir() {
+		return recorded.Mode == current.Mode
+	}
+	return recorded == current
+}
+
+// RemoveUnexpected removes files from .box/bin that no tool installed.
+func (m *Manager) RemoveUnexpected(files []string) error {
+	for _, file := range files {
+		m.log("Removing unexpected file %s...", file)
+		if err := os.RemoveAll(filepath.Join(m.RootDir, file)); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", file, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package installer
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// stateInstaller installs like fakeInstaller and also rewrites a state file
+// shared by all tools, as package managers do.
+type stateInstaller struct {
+	fakeInstaller
+}
+
+func (s *stateInstaller) Install(tool config.Tool, m *Manager, sandbox bool) ([]string, error) {
+	state := filepath.Join(m.RootDir, ".box", "fake", "state")
+	if err := os.MkdirAll(filepath.Dir(state), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(state, []byte(tool.Source.String()), 0600); err != nil {
+		return nil, err
+	}
+	// Make sure the modification time differs from a previous install
+	later := time.Now().Add(time.Duration(s.calls+1) * time.Second)
+	if err := os.Chtimes(state, later, later); err != nil {
+		return nil, err
+	}
+	return s.fakeInstaller.Install(tool, m, sandbox)
+}
+
+func TestVerify(t *testing.T) {
+	root := t.TempDir()
+	m := New(root, t.TempDir(), nil, &config.Config{})
+	m.Output = io.Discard
+	m.RegisterInstaller("fake", &stateInstaller{})
+
+	for _, name := range []string{"hello", "world"} {
+		if err := m.Install(config.Tool{Type: "fake", Source: config.Source{name}}); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+	}
+
+	report, err := m.Verify()
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.Clean() {
+		t.Fatalf("expected a clean report after install, got %+v", report)
+	}
+
+	manifest, err := m.LoadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := manifest.Tools["hello"].Integrity[filepath.Join(".box", "bin", "hello")]
+	if record.Link == "" {
+		t.Errorf("expected the symlink target to be recorded, got %+v", record)
+	}
+
+	// Tamper with the installation
+	if err := os.WriteFile(filepath.Join(root, ".box", "fake", "bin", "hello"), []byte("evil"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(root, ".box", "bin", "world")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".box", "bin", "intruder"), []byte("evil"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err = m.Verify()
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if len(report.Tools) != 2 {
+		t.Fatalf("expected drift in 2 tools, got %+v", report.Tools)
+	}
+	if got := strings.Join(report.Tools[0].Modified, ","); got != filepath.Join(".box", "fake", "bin", "hello") {
+		t.Errorf("expected the hello binary to be modified, got %q", got)
+	}
+	if got := strings.Join(report.Tools[1].Missing, ","); got != filepath.Join(".box", "bin", "world") {
+		t.Errorf("expected the world link to be missing, got %q", got)
+	}
+	if got := strings.Join(report.Unexpected, ","); got != filepath.Join(".box", "bin", "intruder") {
+		t.Errorf("expected an unexpected intruder, got %q", got)
+	}
+}
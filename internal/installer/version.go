@@ -0,0 +1,211 @@
+package installer
+
+import (
+	"bytes"
+	"debug/buildinfo"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// VersionResolver is implemented by installers that can report the version a
+// tool was actually installed at, e.g. when it is configured as "latest".
+type VersionResolver interface {
+	// ResolveVersion returns the installed version of the tool, or "" if it is unknown.
+	ResolveVersion(tool config.Tool, m *Manager) (string, error)
+}
+
+// versionPattern matches version numbers in the output of version commands.
+var versionPattern = regexp.MustCompile(`v?\d+(\.\d+)+([-+][0-9A-Za-z.+-]+)?`)
+
+// distSeparators matches the separators that are normalized in Python distribution names.
+var distSeparators = regexp.MustCompile(`[-_.]+`)
+
+// resolveVersion returns the installed version of a tool from its
+// version_command, or else from its installer, which installed it with
+// installed. Failures are only logged, as the version is informational.
+func (m *Manager) resolveVersion(tool config.Tool, installer Installer, installed *Manager) string {
+	var version string
+	var err error
+	if tool.VersionCommand != "" {
+		version, err = m.runVersionCommand(tool.VersionCommand)
+	} else if resolver, ok := installer.(VersionResolver); ok {
+		version, err = resolver.ResolveVersion(tool, installed)
+	}
+	if err != nil {
+		m.warn("Failed to determine the installed version of %s: %v", tool.DisplayName(), err)
+		return ""
+	}
+	if version != "" {
+		m.log("Installed version of %s: %s", tool.DisplayName(), version)
+	}
+	return version
+}
+
+// runVersionCommand runs a version_command like a post-install hook and
+// extracts the version from its output. Without a version number, the first
+// line of the output is used.
+func (m *Manager) runVersionCommand(command string) (string, error) {
+	var buf bytes.Buffer
+	if err := m.runCommandTo(&buf, "sh", []string{"-c", command}, m.scriptEnv(), m.RootDir, true); err != nil {
+		return "", fmt.Errorf("version command %q failed: %w", command, err)
+	}
+	if version := versionPattern.FindString(buf.String()); version != "" {
+		return version, nil
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(buf.String()), "\n")
+	return strings.TrimSpace(line), nil
+}
+
+// packageName returns the package name of a source without its version.
+func packageName(source string) string {
+	if i := strings.Index(source, "=="); i != -1 {
+		source = source[:i]
+	}
+	// Scoped npm packages start with @
+	if i := strings.LastIndex(source, "@"); i > 0 {
+		source = source[:i]
+	}
+	return source
+}
+
+// ResolveVersion reads the main module version from the build info of the
+// first binary, like 'go version -m'.
+func (i *GoInstaller) ResolveVersion(tool config.Tool, m *Manager) (string, error) {
+	binaries := tool.Binaries
+	if len(binaries) == 0 {
+		binaries = []string{m.detectBinaryName(tool.Source.String())}
+	}
+	binary, err := m.findBinary(filepath.Join(m.RootDir, ".box", "go", "bin"), binaries[0])
+	if err != nil {
+		return "", err
+	}
+	info, err := buildinfo.ReadFile(binary)
+	if err != nil {
+		return "", fmt.Errorf("failed to read build info of %s: %w", binary, err)
+	}
+	if info.Main.Version == "(devel)" {
+		return "", nil
+	}
+	return info.Main.Version, nil
+}
+
+// ResolveVersion reads the version from the package.json of the globally
+// installed package, which is what 'npm ls -g --json' reports.
+func (i *NpmInstaller) ResolveVersion(tool config.Tool, m *Manager) (string, error) {
+	npmDir := filepath.Join(m.RootDir, ".box", "npm")
+	modules := filepath.Join(npmDir, "lib", "node_modules")
+	if runtime.GOOS == "windows" {
+		modules = filepath.Join(npmDir, "node_modules")
+	}
+
+	data, err := os.ReadFile(filepath.Join(modules, packageName(tool.Source.String()), "package.json"))
+	if err != nil {
+		return "", err
+	}
+	var pkg struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return "", fmt.Errorf("failed to parse package.json: %w", err)
+	}
+	return pkg.Version, nil
+}
+
+// ResolveVersion reads the version from the dist-info directory of the
+// package in the tool's environment, which is what 'uv tool list' reports.
+func (i *UvInstaller) ResolveVersion(tool config.Tool, m *Manager) (string, error) {
+	name := packageName(tool.Source.String())
+	if i := strings.IndexAny(name, "[<>=~! "); i != -1 {
+		name = name[:i]
+	}
+	// Distribution names are normalized in dist-info directory names, and uv
+	// names the environment after the PEP 503 normalized name
+	normalized := strings.ToLower(distSeparators.ReplaceAllString(name, "_"))
+
+	env := filepath.Join(m.RootDir, ".box", "uv", strings.ToLower(distSeparators.ReplaceAllString(name, "-")))
+	patterns := []string{
+		filepath.Join(env, "lib", "python*", "site-packages", "*.dist-info"),
+		filepath.Join(env, "Lib", "site-packages", "*.dist-info"),
+	}
+	for _, pattern := range patterns {
+		dirs, err := filepath.Glob(pattern)
+		if err != nil {
+			return "", err
+		}
+		for _, dir := range dirs {
+			dist, version, ok := strings.Cut(strings.TrimSuffix(filepath.Base(dir), ".dist-info"), "-")
+			if ok && strings.ToLower(dist) == normalized {
+				return version, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// ResolveVersion reads the version from the newest gemspec of the gem in
+// .box/gems/specifications, which is what 'gem list' reports.
+func (i *GemInstaller) ResolveVersion(tool config.Tool, m *Manager) (string, error) {
+	name := tool.Source.String()
+	specs, err := filepath.Glob(filepath.Join(m.RootDir, ".box", "gems", "specifications", name+"-*.gemspec"))
+	if err != nil {
+		return "", err
+	}
+
+	type spec struct {
+		version string
+		modTime int64
+	}
+	var candidates []spec
+	for _, path := range specs {
+		version := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), name+"-"), ".gemspec")
+		// Skip gems whose name starts with this one, e.g. rake-compiler for rake
+		if version == "" || version[0] < '0' || version[0] > '9' {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			candidates = append(candidates, spec{version, info.ModTime().UnixNano()})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", nil
+	}
+	// The gem that was just installed has the newest spec
+	sort.Slice(candidates, func(a, b int) bool { return candidates[a].modTime > candidates[b].modTime })
+	return candidates[0].version, nil
+}
+
+// ResolveVersion reads the version from the install records that
+// cargo-binstall keeps in .crates2.json in its root.
+func (i *CargoInstaller) ResolveVersion(tool config.Tool, m *Manager) (string, error) {
+	name := packageName(tool.Source.String())
+	// Offline installs copy the binaries from the cache, whose records apply
+	for _, root := range []string{filepath.Join(m.RootDir, ".box", "cargo"), m.cachePath("cargo")} {
+		data, err := os.ReadFile(filepath.Clean(filepath.Join(root, ".crates2.json")))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		var records struct {
+			Installs map[string]json.RawMessage `json:"installs"`
+		}
+		if err := json.Unmarshal(data, &records); err != nil {
+			return "", fmt.Errorf("failed to parse .crates2.json: %w", err)
+		}
+		// Keys have the form "<name> <version> (<source>)"
+		for key := range records.Installs {
+			if fields := strings.Fields(key); len(fields) >= 2 && fields[0] == name {
+				return fields[1], nil
+			}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,145 @@
+package installer
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveVersion(t *testing.T) {
+	root := t.TempDir()
+	m := New(root, t.TempDir(), nil, &config.Config{})
+	box := filepath.Join(root, ".box")
+
+	modules := filepath.Join(box, "npm", "lib", "node_modules")
+	if runtime.GOOS == "windows" {
+		modules = filepath.Join(box, "npm", "node_modules")
+	}
+	writeTestFile(t, filepath.Join(modules, "@biomejs", "biome", "package.json"), `{"name": "@biomejs/biome", "version": "1.9.4"}`)
+	writeTestFile(t, filepath.Join(box, "uv", "pre-commit", "lib", "python3.12", "site-packages", "pre_commit-4.0.1.dist-info", "METADATA"), "")
+	writeTestFile(t, filepath.Join(box, "uv", "sphinx-autobuild", "lib", "python3.12", "site-packages", "sphinx_autobuild-2024.10.3.dist-info", "METADATA"), "")
+	writeTestFile(t, filepath.Join(box, "gems", "specifications", "rake-13.2.1.gemspec"), "")
+	writeTestFile(t, filepath.Join(box, "gems", "specifications", "rake-compiler-1.2.0.gemspec"), "")
+	writeTestFile(t, filepath.Join(box, "cargo", ".crates2.json"), `{"installs": {"ripgrep 14.1.1 (registry+https://github.com/rust-lang/crates.io-index)": {"bins": ["rg"]}}}`)
+
+	tests := []struct {
+		resolver VersionResolver
+		tool     config.Tool
+		want     string
+	}{
+		{&NpmInstaller{}, config.Tool{Type: "npm", Source: config.Source{"@biomejs/biome@latest"}}, "1.9.4"},
+		{&UvInstaller{}, config.Tool{Type: "uv", Source: config.Source{"pre-commit"}}, "4.0.1"},
+		{&UvInstaller{}, config.Tool{Type: "uv", Source: config.Source{"Sphinx_Autobuild>=2024"}}, "2024.10.3"},
+		{&GemInstaller{}, config.Tool{Type: "gem", Source: config.Source{"rake"}}, "13.2.1"},
+		{&CargoInstaller{}, config.Tool{Type: "cargo", Source: config.Source{"ripgrep"}}, "14.1.1"},
+		{&CargoInstaller{}, config.Tool{Type: "cargo", Source: config.Source{"fd-find"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool.Type+" "+tt.tool.Source.String(), func(t *testing.T) {
+			got, err := tt.resolver.ResolveVersion(tt.tool, m)
+			if err != nil {
+				t.Fatalf("ResolveVersion failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected version %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGoResolveVersion(t *testing.T) {
+	root := t.TempDir()
+	m := New(root, t.TempDir(), nil, &config.Config{})
+
+	// The test binary carries build info like any binary built by go install
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := "hello"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".box", "go", "bin"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := copyFile(self, filepath.Join(root, ".box", "go", "bin", name), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	version, err := (&GoInstaller{}).ResolveVersion(config.Tool{Type: "go", Source: config.Source{"example.com/hello"}}, m)
+	if err != nil {
+		t.Fatalf("ResolveVersion failed: %v", err)
+	}
+	if version != "" && version[0] != 'v' {
+		t.Errorf("expected a module version or none for a development build, got %q", version)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, ".box", "go", "bin", name), []byte("not a binary"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&GoInstaller{}).ResolveVersion(config.Tool{Type: "go", Source: config.Source{"example.com/hello"}}, m); err == nil {
+		t.Error("expected an error for a file without build info")
+	}
+}
+
+func TestVersionCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("version commands run with sh")
+	}
+
+	m := New(t.TempDir(), t.TempDir(), nil, &config.Config{})
+	m.Output = io.Discard
+	m.RegisterInstaller("fake", &fakeInstaller{})
+
+	tool := config.Tool{Type: "fake", Source: config.Source{"hello"}, Version: "latest", VersionCommand: "echo 'hello version v2.3.4-rc.1 (built today)'"}
+	if err := m.Install(tool); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	manifest, err := m.LoadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := manifest.Tools["hello"]
+	if info.Version != "latest" || info.ResolvedVersion != "v2.3.4-rc.1" {
+		t.Errorf("expected version latest resolved to v2.3.4-rc.1, got %q and %q", info.Version, info.ResolvedVersion)
+	}
+
+	// A failing version command does not fail the install
+	tool.VersionCommand = "exit 1"
+	if err := m.Install(tool); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if manifest, _ := m.LoadManifest(); manifest.Tools["hello"].ResolvedVersion != "" {
+		t.Errorf("expected no resolved version, got %q", manifest.Tools["hello"].ResolvedVersion)
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	tests := map[string]string{
+		"cowsay":                "cowsay",
+		"cowsay@1.6.0":          "cowsay",
+		"@biomejs/biome":        "@biomejs/biome",
+		"@biomejs/biome@^1.9.0": "@biomejs/biome",
+		"ruff==0.6.9":           "ruff",
+	}
+	for source, want := range tests {
+		if got := packageName(source); got != want {
+			t.Errorf("packageName(%q) = %q, want %q", source, got, want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+// Package logging provides the log/slog handlers used by box: human-readable
+// lines for the terminal, a fan-out to additional handlers such as a JSON log
+// file, and redaction of secrets.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LevelTrace is more verbose than slog.LevelDebug and enabled with -vv.
+const LevelTrace = slog.Level(-8)
+
+// LevelFor returns the level of the given number of -v flags, or
+// slog.LevelWarn if quiet is set.
+func LevelFor(verbosity int, quiet bool) slog.Level {
+	switch {
+	case quiet:
+		return slog.LevelWarn
+	case verbosity >= 2:
+		return LevelTrace
+	case verbosity == 1:
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Handler writes records as human-readable lines: the message followed by its
+// attributes as key=value pairs. Records of other levels than Info are
+// prefixed with their level.
+type Handler struct {
+	w     io.Writer
+	level slog.Leveler
+	attrs []slog.Attr
+	mu    *sync.Mutex
+}
+
+// NewHandler returns a Handler writing records of at least level to w. A nil
+// writer discards all records.
+func NewHandler(w io.Writer, level slog.Leveler) *Handler {
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	return &Handler{w: w, level: level, mu: &sync.Mutex{}}
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.w != nil && level >= h.level.Level()
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	switch {
+	case r.Level <= LevelTrace:
+		b.WriteString("trace: ")
+	case r.Level < slog.LevelInfo:
+		b.WriteString("debug: ")
+	case r.Level >= slog.LevelError:
+		b.WriteString("error: ")
+	case r.Level >= slog.LevelWarn:
+		b.WriteString("warning: ")
+	}
+	b.WriteString(r.Message)
+
+	write := func(a slog.Attr) bool {
+		if a.Equal(slog.Attr{}) {
+			return true
+		}
+		b.WriteString(" " + a.Key + "=" + formatValue(a.Value.Resolve()))
+		return true
+	}
+	for _, a := range h.attrs {
+		write(a)
+	}
+	r.Attrs(write)
+	b.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func formatValue(v slog.Value) string {
+	s := v.String()
+	if v.Kind() == slog.KindString && (s == "" || strings.ContainsAny(s, " \t\n\"=")) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+// WithGroup implements slog.Handler. Groups are not shown in the terminal.
+func (h *Handler) WithGroup(_ string) slog.Handler {
+	return h
+}
+
+// multiHandler passes records to all handlers that are enabled for them.
+type multiHandler []slog.Handler
+
+// Multi returns a handler that passes records to all given handlers. Nil
+// handlers are skipped.
+func Multi(handlers ...slog.Handler) slog.Handler {
+	var multi multiHandler
+	for _, h := range handlers {
+		if h != nil {
+			multi = append(multi, h)
+		}
+	}
+	if len(multi) == 1 {
+		return multi[0]
+	}
+	return multi
+}
+
+func (m multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range m {
+		if h.Enabled(ctx, r.Level) {
+			if err := h.Handle(ctx, r.Clone()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to write log record: %v", errs)
+	}
+	return nil
+}
+
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(multiHandler, len(m))
+	for i, h := range m {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make(multiHandler, len(m))
+	for i, h := range m {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
+
+// secretName matches names of environment variables that hold secrets.
+var secretName = regexp.MustCompile(`(?i)(token|secret|passw|key|auth|credential)`)
+
+// RedactEnv returns a copy of env in KEY=VALUE form with the values of
+// secret variables hidden and credentials removed from URLs.
+func RedactEnv(env []string) []string {
+	redacted := make([]string, len(env))
+	for i, e := range env {
+		key, value, ok := strings.Cut(e, "=")
+		switch {
+		case !ok:
+			redacted[i] = e
+		case secretName.MatchString(key):
+			redacted[i] = key + "=xxxxx"
+		default:
+			redacted[i] = key + "=" + RedactURL(value)
+		}
+	}
+	return redacted
+}
+
+// RedactURL hides the user info of s if it is a URL with credentials.
+func RedactURL(s string) string {
+	if !strings.Contains(s, "://") {
+		return s
+	}
+	if u, err := url.Parse(s); err == nil && u.User != nil {
+		u.User = url.User("xxxxx")
+		return u.String()
+	}
+	return s
+}
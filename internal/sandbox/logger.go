@@ -0,0 +1,11 @@
+package sandbox
+
+import "log/slog"
+
+// logger returns log, or a logger discarding all messages if log is nil.
+func logger(log *slog.Logger) *slog.Logger {
+	if log == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return log
+}
@@ -5,6 +5,7 @@ package sandbox
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -12,7 +13,8 @@ import (
 
 // Apply configures the command to run within a sandbox on macOS using sandbox-exec.
 // It allows write access to the project root, the .box directory, and the specified tempDir.
-func Apply(_ *exec.Cmd, name string, args []string, rootDir string, tempDir string) (string, []string) {
+// The sandbox configuration is logged at debug level to log, which may be nil.
+func Apply(_ *exec.Cmd, name string, args []string, rootDir string, tempDir string, log *slog.Logger) (string, []string) {
 	if tempDir == "" {
 		tempDir = os.TempDir()
 	}
@@ -49,5 +51,6 @@ func Apply(_ *exec.Cmd, name string, args []string, rootDir string, tempDir stri
 (allow file-write* (literal "/dev/stderr"))
 `, resolvedRoot, tempDir, resolvedTemp, systemTemp, resolvedSystemTemp)
 
+	logger(log).Debug("sandbox", "kind", "sandbox-exec", "writable", []string{resolvedRoot, resolvedTemp, resolvedSystemTemp}, "command", name)
 	return "sandbox-exec", append([]string{"-p", profile, name}, args...)
 }
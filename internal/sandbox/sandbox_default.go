@@ -4,10 +4,13 @@
 package sandbox
 
 import (
+	"log/slog"
 	"os/exec"
+	"runtime"
 )
 
 // Apply is a no-op on unsupported platforms.
-func Apply(_ *exec.Cmd, name string, args []string, _ string, _ string) (string, []string) {
+func Apply(_ *exec.Cmd, name string, args []string, _ string, _ string, log *slog.Logger) (string, []string) {
+	logger(log).Debug("sandbox not supported", "os", runtime.GOOS, "command", name)
 	return name, args
 }
@@ -4,6 +4,7 @@
 package sandbox
 
 import (
+	"log/slog"
 	"os"
 	"os/exec"
 	"syscall"
@@ -11,7 +12,8 @@ import (
 
 // Apply configures the command to run within a sandbox on Linux using User Namespaces.
 // It maps the current user and group to root inside the namespace but disables setgroups.
-func Apply(cmd *exec.Cmd, name string, args []string, _ string, _ string) (string, []string) {
+// The sandbox configuration is logged at debug level to log, which may be nil.
+func Apply(cmd *exec.Cmd, name string, args []string, _ string, _ string, log *slog.Logger) (string, []string) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS,
 		UidMappings: []syscall.SysProcIDMap{
@@ -22,5 +24,6 @@ func Apply(cmd *exec.Cmd, name string, args []string, _ string, _ string) (strin
 		},
 		GidMappingsEnableSetgroups: false,
 	}
+	logger(log).Debug("sandbox", "kind", "user namespace", "uid", os.Getuid(), "gid", os.Getgid(), "command", name)
 	return name, args
 }
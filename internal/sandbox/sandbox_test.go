@@ -9,7 +9,7 @@ import (
 
 func TestApply(t *testing.T) {
 	cmd := exec.Command("ls")
-	name, args := Apply(cmd, "ls", []string{"-la"}, "/root", "/tmp")
+	name, args := Apply(cmd, "ls", []string{"-la"}, "/root", "/tmp", nil)
 
 	switch runtime.GOOS {
 	case "darwin":
@@ -0,0 +1,333 @@
+// Package store implements the optional shared tool store. Tools are installed
+// once into a content-addressed directory and linked into each project's .box/bin.
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/sebakri/box/internal/config"
+)
+
+// EnvVar enables ("1", "true") or disables ("0", "false") the shared store regardless of box.yml.
+const EnvVar = "BOX_SHARED_STORE"
+
+const (
+	entryFile    = "entry.json"
+	registryFile = "projects.json"
+)
+
+// Store is a directory of installed tools keyed by a hash of their definition.
+type Store struct {
+	Dir string
+}
+
+// Entry describes an installed store entry.
+type Entry struct {
+	Key      string    `json:"key"`
+	Type     string    `json:"type"`
+	Source   string    `json:"source"`
+	Version  string    `json:"version,omitempty"`
+	Args     []string  `json:"args,omitempty"`
+	Binaries []string  `json:"binaries,omitempty"`
+	Platform string    `json:"platform"`
+	Created  time.Time `json:"created"`
+}
+
+// Default returns the store in the user's cache directory
+// ($XDG_CACHE_HOME/box/store on Linux).
+func Default() (*Store, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine cache directory: %w", err)
+	}
+	return &Store{Dir: filepath.Join(cacheDir, "box", "store")}, nil
+}
+
+// Key returns the store key of a tool. It covers everything that influences
+// the installed files: type, source, version, args, binaries and platform.
+func Key(tool config.Tool) string {
+	data, _ := json.Marshal(newEntry(tool))
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func newEntry(tool config.Tool) Entry {
+	return Entry{
+		Type:     tool.Type,
+		Source:   tool.Source.String(),
+		Version:  tool.Version,
+		Args:     tool.Args,
+		Binaries: tool.Binaries,
+		Platform: runtime.GOOS + "/" + runtime.GOARCH,
+	}
+}
+
+// CacheDir returns the directory of the package manager caches that all
+// entries share, such as the Go module cache.
+func (s *Store) CacheDir() string {
+	return filepath.Join(s.Dir, "cache")
+}
+
+// Path returns the directory of the entry with the given key.
+func (s *Store) Path(key string) string {
+	return filepath.Join(s.Dir, key)
+}
+
+// Acquire prepares the entry for tool. If the entry is already installed,
+// complete is true and release is nil. Otherwise the entry is locked and the
+// caller must install into dir and call release with the outcome. A failed
+// install leaves no entry behind.
+func (s *Store) Acquire(tool config.Tool) (dir string, complete bool, release func(ok bool) error, err error) {
+	key := Key(tool)
+	dir = s.Path(key)
+	if _, err := os.Stat(filepath.Join(dir, entryFile)); err == nil {
+		return dir, true, nil, nil
+	}
+
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return "", false, nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	lockPath := dir + ".lock"
+	lock, err := os.OpenFile(filepath.Clean(lockPath), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return "", false, nil, fmt.Errorf("store entry %s is being installed by another process (remove %s if it is stale)", key, lockPath)
+		}
+		return "", false, nil, err
+	}
+	_ = lock.Close()
+
+	// Remove leftovers of an interrupted install
+	if err := removeAll(dir); err != nil {
+		_ = os.Remove(lockPath)
+		return "", false, nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		_ = os.Remove(lockPath)
+		return "", false, nil, err
+	}
+
+	release = func(ok bool) error {
+		defer func() { _ = os.Remove(lockPath) }()
+		if !ok {
+			return removeAll(dir)
+		}
+		entry := newEntry(tool)
+		entry.Key = key
+		entry.Created = time.Now()
+		data, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return err
+		}
+		// The entry file marks the install as complete, so it is written last
+		return os.WriteFile(filepath.Join(dir, entryFile), data, 0600)
+	}
+	return dir, false, release, nil
+}
+
+// Entries returns all complete entries sorted by source.
+func (s *Store) Entries() ([]Entry, error) {
+	dirs, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.Dir, d.Name(), entryFile))
+		if err != nil {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Source != entries[j].Source {
+			return entries[i].Source < entries[j].Source
+		}
+		return entries[i].Version < entries[j].Version
+	})
+	return entries, nil
+}
+
+// Size returns the disk usage of the entry with the given key in bytes.
+func (s *Store) Size(key string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(s.Path(key), func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// Register records a project using the store so that its entries are kept by GC.
+func (s *Store) Register(projectRoot string) error {
+	projects, err := s.Projects()
+	if err != nil {
+		return err
+	}
+	for _, p := range projects {
+		if p == projectRoot {
+			return nil
+		}
+	}
+	return s.saveProjects(append(projects, projectRoot))
+}
+
+// Projects returns the registered project roots.
+func (s *Store) Projects() ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(s.Dir), registryFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var projects []string
+	if err := json.Unmarshal(data, &projects); err != nil {
+		return nil, fmt.Errorf("failed to parse project registry: %w", err)
+	}
+	return projects, nil
+}
+
+func (s *Store) saveProjects(projects []string) error {
+	sort.Strings(projects)
+	data, err := json.MarshalIndent(projects, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Dir), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(filepath.Dir(s.Dir), registryFile), data, 0600)
+}
+
+// References returns the store keys used by each registered project. Projects
+// that no longer have a manifest are omitted.
+func (s *Store) References() (map[string][]string, error) {
+	projects, err := s.Projects()
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[string][]string)
+	for _, project := range projects {
+		keys, err := projectKeys(project)
+		if err != nil {
+			continue
+		}
+		refs[project] = keys
+	}
+	return refs, nil
+}
+
+// projectKeys reads the store keys referenced by the manifest of a project.
+func projectKeys(projectRoot string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(projectRoot, ".box", "manifest.json"))
+	if err != nil {
+		return nil, err
+	}
+	var manifest struct {
+		Tools map[string]struct {
+			Store string `json:"store"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, t := range manifest.Tools {
+		if t.Store != "" {
+			keys = append(keys, t.Store)
+		}
+	}
+	return keys, nil
+}
+
+// GC removes all entries that are not referenced by any registered project and
+// drops projects without a manifest from the registry. With dryRun nothing is
+// removed. It returns the unreferenced entries.
+func (s *Store) GC(dryRun bool) ([]Entry, error) {
+	refs, err := s.References()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	projects := make([]string, 0, len(refs))
+	for project, keys := range refs {
+		projects = append(projects, project)
+		for _, key := range keys {
+			used[key] = true
+		}
+	}
+
+	var removed []Entry
+	for _, e := range entries {
+		if used[e.Key] {
+			continue
+		}
+		removed = append(removed, e)
+		if !dryRun {
+			if err := removeAll(s.Path(e.Key)); err != nil {
+				return removed, fmt.Errorf("failed to remove %s: %w", e.Key, err)
+			}
+		}
+	}
+
+	if dryRun {
+		return removed, nil
+	}
+	return removed, s.saveProjects(projects)
+}
+
+// removeAll removes dir even if it contains read-only directories, as created
+// by the Go module cache.
+func removeAll(dir string) error {
+	err := os.RemoveAll(dir)
+	if err == nil || !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			// Directories must be writable to remove their contents
+			//nolint:gosec
+			_ = os.Chmod(path, 0700)
+		}
+		return nil
+	})
+	return os.RemoveAll(dir)
+}
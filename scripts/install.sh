@@ -26,16 +26,20 @@ fi
 REPO="sebakri/box"
 BINARY="box-${OS}-${ARCH}${SUFFIX}"
 
-# Get latest release tag
-echo "Detecting latest version..."
-LATEST_TAG=$(curl -s "https://api.github.com/repos/${REPO}/releases/latest" | grep '"tag_name":' | sed -E 's/.*"([^"]+)".*/\1/')
+# Use the release given by BOX_VERSION, or the latest one
+if [ -n "${BOX_VERSION}" ]; then
+    TAG="${BOX_VERSION}"
+else
+    echo "Detecting latest version..."
+    TAG=$(curl -s "https://api.github.com/repos/${REPO}/releases/latest" | grep '"tag_name":' | sed -E 's/.*"([^"]+)".*/\1/')
 
-if [ -z "${LATEST_TAG}" ] || [ "${LATEST_TAG}" = "null" ]; then
-    echo "Failed to detect latest version. This might happen if there are no releases yet or if you've hit GitHub API rate limits."
-    exit 1
+    if [ -z "${TAG}" ] || [ "${TAG}" = "null" ]; then
+        echo "Failed to detect latest version. This might happen if there are no releases yet or if you've hit GitHub API rate limits."
+        exit 1
+    fi
 fi
 
-DOWNLOAD_URL="https://github.com/${REPO}/releases/download/${LATEST_TAG}/${BINARY}"
+DOWNLOAD_URL="https://github.com/${REPO}/releases/download/${TAG}/${BINARY}"
 INSTALL_DIR="/usr/local/bin"
 
 # Allow overriding install directory
@@ -43,10 +47,10 @@ if [ -n "${BOX_INSTALL_DIR}" ]; then
     INSTALL_DIR="${BOX_INSTALL_DIR}"
 fi
 
-echo "Downloading box ${LATEST_TAG} for ${OS}/${ARCH}..."
+echo "Downloading box ${TAG} for ${OS}/${ARCH}..."
 if ! curl -sSfL -o "box" "${DOWNLOAD_URL}"; then
     echo "Failed to download binary from ${DOWNLOAD_URL}."
-    echo "Please check if a binary for your platform (${OS}/${ARCH}) is available in the ${LATEST_TAG} release."
+    echo "Please check if a binary for your platform (${OS}/${ARCH}) is available in the ${TAG} release."
     exit 1
 fi
 
@@ -208,11 +208,82 @@ func TestCLICommands(t *testing.T) {
 		}
 	})
 
+	t.Run("generate devcontainer", func(t *testing.T) {
+		runBoxCommand(t, projectDir, "generate", "devcontainer")
+		devcontainerPath := filepath.Join(projectDir, ".devcontainer", "devcontainer.json")
+		content, err := os.ReadFile(devcontainerPath)
+		if err != nil {
+			t.Fatalf("Expected devcontainer.json to be generated, but not found: %v", err)
+		}
+		if !strings.Contains(string(content), "box install -y") {
+			t.Errorf("devcontainer.json missing postCreateCommand: %s", string(content))
+		}
+	})
+
 	t.Run("doctor", func(t *testing.T) {
 		runBoxCommand(t, projectDir, "doctor")
 	})
 }
 
+func TestShimFailsWithBrokenConfig(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the shim is a shell script")
+	}
+
+	// The shim runs the box executable, so the test builds one
+	boxBin := filepath.Join(t.TempDir(), "box")
+	//nolint:gosec
+	if out, err := exec.Command("go", "build", "-o", boxBin, "../..").CombinedOutput(); err != nil {
+		t.Fatalf("failed to build box: %v\n%s", err, out)
+	}
+
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, "box.yml")
+	content := `env:
+  FOO: bar
+tools:
+  - type: script
+    alias: hello
+    shim: true
+    source:
+      - printf '#!/bin/sh\necho "FOO=$FOO"\n' > "$BOX_BIN_DIR/hello" && chmod +x "$BOX_BIN_DIR/hello"
+`
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	runBoxCommand(t, projectDir, "install", "--non-interactive")
+
+	shim := filepath.Join(projectDir, ".box", "bin", "hello")
+	runShim := func(env ...string) (string, error) {
+		//nolint:gosec
+		c := exec.Command(shim)
+		c.Env = append(os.Environ(), append(env, "BOX="+boxBin)...)
+		out, err := c.CombinedOutput()
+		return string(out), err
+	}
+	if out, err := runShim(); err != nil || !strings.Contains(out, "FOO=bar") {
+		t.Fatalf("expected the shim to run with the project env, got %q (%v)", out, err)
+	}
+
+	if out, err := runShim(config.OverlayEnv + "=" + filepath.Join(projectDir, "missing.yml")); err == nil {
+		t.Errorf("expected the shim to fail with a broken overlay, got %q", out)
+	}
+
+	if err := os.WriteFile(configPath, []byte("extends: [missing.yml]\n"+content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := runShim(); err == nil {
+		t.Errorf("expected the shim to fail with a missing extended file, got %q", out)
+	}
+
+	if err := os.Remove(configPath); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := runShim(); err == nil {
+		t.Errorf("expected the shim to fail without its configuration, got %q", out)
+	}
+}
+
 func runBoxCommand(t *testing.T, projectDir string, args ...string) string {
 	t.Helper()
 	